@@ -0,0 +1,152 @@
+package alailog
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"path"
+	"time"
+)
+
+// AccessLogConfig configures the access logging middleware.
+//
+//	Level: maps a response status to the level of its entry (nil uses AccessLogLevel)
+//	ExcludePaths: path.Match patterns of request paths that are not logged, e.g. "/healthz" or "/static/*"
+//	CombinedLogFormat: whether entries use the Combined Log Format instead of a message with fields
+type AccessLogConfig struct {
+	Level             func(status int) Level
+	ExcludePaths      []string
+	CombinedLogFormat bool
+}
+
+// AccessLogLevel is the default status to level mapping: Error for 5xx responses,
+// Warn for 4xx responses and Info otherwise.
+func AccessLogLevel(status int) Level {
+	switch {
+	case status >= 500:
+		return ErrorLvl
+	case status >= 400:
+		return WarnLvl
+	default:
+		return InfoLvl
+	}
+}
+
+// AccessLogMiddleware returns an http.Handler that logs every request served by next:
+// method, path, status, bytes written, duration, remote address and user agent.
+// By default the entry reads "GET /orders 200 512B 1.2ms" with the details as fields,
+// and any fields carried by the request context (such as trace IDs) are attached.
+//
+// Example usage:
+//
+//	http.ListenAndServe(":8080", logger.AccessLogMiddleware(mux, alailog.AccessLogConfig{
+//	    ExcludePaths: []string{"/healthz"},
+//	}))
+func (l *Logger) AccessLogMiddleware(next http.Handler, config ...AccessLogConfig) http.Handler {
+	var c AccessLogConfig
+	if len(config) > 0 {
+		c = config[0]
+	}
+	if c.Level == nil {
+		c.Level = AccessLogLevel
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c.excluded(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		start := time.Now()
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		elapsed := l.elapsedExecutionTime(start)
+
+		level := c.Level(rec.status)
+		if c.CombinedLogFormat {
+			l.Log(level, combinedLogLine(r, rec, start)+"\n")
+			return
+		}
+		ctx := ContextWithFields(r.Context(), Fields{
+			"method":      r.Method,
+			"path":        r.URL.Path,
+			"status":      rec.status,
+			"bytes":       rec.bytes,
+			"duration":    elapsed,
+			"remote_addr": r.RemoteAddr,
+			"user_agent":  r.UserAgent(),
+		})
+		l.LogCtx(ctx, level, fmt.Sprintf("%s %s %d %dB %v\n", r.Method, r.URL.Path, rec.status, rec.bytes, elapsed))
+	})
+}
+
+// excluded reports whether the request path matches one of the excluded patterns.
+func (c AccessLogConfig) excluded(requestPath string) bool {
+	for _, pattern := range c.ExcludePaths {
+		if ok, _ := path.Match(pattern, requestPath); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// combinedLogLine formats a request in the Combined Log Format:
+//
+//	host ident authuser [date] "request line" status bytes "referer" "user agent"
+func combinedLogLine(r *http.Request, rec *responseRecorder, start time.Time) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	user := "-"
+	if username, _, ok := r.BasicAuth(); ok && username != "" {
+		user = username
+	}
+	return fmt.Sprintf("%s - %s [%s] \"%s %s %s\" %d %d %q %q",
+		orDash(host), user, start.Format("02/Jan/2006:15:04:05 -0700"),
+		r.Method, r.RequestURI, r.Proto, rec.status, rec.bytes,
+		orDash(r.Referer()), orDash(r.UserAgent()))
+}
+
+// orDash returns s, or "-" when s is empty, as the Combined Log Format expects.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// responseRecorder is an http.ResponseWriter that records the status and the number of bytes written.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+// WriteHeader records the status before sending it.
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write counts the bytes written to the response.
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// Flush sends buffered data to the client when the underlying writer supports it.
+func (r *responseRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,64 @@
+package alailog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestLogger_AccessLogMiddleware(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		case "/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.Write([]byte("hello"))
+		}
+	})
+
+	tests := []struct {
+		name   string
+		level  Level
+		config AccessLogConfig
+		path   string
+		want   string
+	}{
+		{
+			name: "ok",
+			path: "/orders",
+			want: `^GET /orders 200 5B \S+ bytes=5 duration=\S+ method=GET path=/orders remote_addr=192\.0\.2\.1:1234 status=200 user_agent=test-agent\n$`,
+		},
+		{name: "4xx is a warning", level: WarnLvl, path: "/missing", want: `^GET /missing 404 `},
+		{name: "5xx is an error", level: ErrorLvl, path: "/broken", want: `^GET /broken 500 0B `},
+		{name: "excluded", config: AccessLogConfig{ExcludePaths: []string{"/static/*"}}, path: "/static/app.js", want: `^$`},
+		{
+			name:   "custom levels",
+			level:  ErrorLvl,
+			config: AccessLogConfig{Level: func(int) Level { return ErrorLvl }},
+			path:   "/orders",
+			want:   `^GET /orders 200 `,
+		},
+		{
+			name:   "combined log format",
+			config: AccessLogConfig{CombinedLogFormat: true},
+			path:   "/orders?id=1",
+			want:   `^192\.0\.2\.1 - - \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "GET /orders\?id=1 HTTP/1\.1" 200 5 "-" "test-agent"\n$`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, output := newTestLogger(t, tt.level)
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.RemoteAddr = "192.0.2.1:1234"
+			req.Header.Set("User-Agent", "test-agent")
+			l.AccessLogMiddleware(handler, tt.config).ServeHTTP(httptest.NewRecorder(), req)
+
+			if got := output(); !regexp.MustCompile(tt.want).MatchString(got) {
+				t.Errorf("AccessLogMiddleware() logged %q, want match for %q", got, tt.want)
+			}
+		})
+	}
+}
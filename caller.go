@@ -0,0 +1,78 @@
+package alailog
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// Caller is the location of the code that logged an entry.
+type Caller struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// String returns the file and line of the caller.
+func (c Caller) String() string {
+	return fmt.Sprintf("%s:%d", c.File, c.Line)
+}
+
+// packagePrefix prefixes the names of the functions of this package, skipped when looking up callers.
+var packagePrefix = reflect.TypeOf(Logger{}).PkgPath() + "."
+
+// GetCaller returns the first caller up the call stack that is outside this package, which is the
+// code that called the logger however many of its methods are in between.
+func (d *Debugger) GetCaller() Caller {
+	caller, _ := externalCaller()
+	return caller
+}
+
+// EnableCallers records in every entry the function, file and line that logged it, for the
+// formatters and sinks reporting the origin of entries, such as ECSFormatter.
+func (l *Logger) EnableCallers() {
+	l.Callers = true
+}
+
+// DisableCallers stops recording the caller of entries.
+func (l *Logger) DisableCallers() {
+	l.Callers = false
+}
+
+// runtimeFrames holds the prefixes of the functions externalCaller skips: the runtime and
+// timer frames found below panics and the goroutines started by the package.
+var runtimeFrames = []string{"runtime.", "time."}
+
+// externalCaller returns the first caller outside this package, skipping runtime and timer
+// frames. Tests of the package count as outside callers. It returns false for the entries
+// logged by the package itself, e.g. from a timer, which have no outside caller.
+func externalCaller() (Caller, bool) {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if isExternalFrame(frame) {
+			return Caller{Function: frame.Function, File: frame.File, Line: frame.Line}, true
+		}
+		if !more {
+			return Caller{}, false
+		}
+	}
+}
+
+// isExternalFrame reports whether a frame belongs to a caller of the package.
+func isExternalFrame(frame runtime.Frame) bool {
+	if strings.HasSuffix(frame.File, "_test.go") {
+		return true
+	}
+	if strings.HasPrefix(frame.Function, packagePrefix) {
+		return false
+	}
+	for _, prefix := range runtimeFrames {
+		if strings.HasPrefix(frame.Function, prefix) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package alailog
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLogger_EnableCallers(t *testing.T) {
+	l, _ := newTestLogger(t, InfoLvl)
+	var callers []*Caller
+	l.formatter = FormatterFunc(func(entry *Entry) ([]byte, error) {
+		callers = append(callers, entry.Caller)
+		return nil, nil
+	})
+
+	l.Info("without caller\n")
+	l.EnableCallers()
+	l.Infof("with caller %d\n", 1)
+	l.DisableCallers()
+	l.Info("without caller\n")
+
+	if len(callers) != 3 || callers[0] != nil || callers[2] != nil {
+		t.Fatalf("callers = %v, want only the second entry with a caller", callers)
+	}
+	caller := callers[1]
+	if caller == nil || !strings.HasSuffix(caller.File, "caller_test.go") || !strings.HasSuffix(caller.Function, "TestLogger_EnableCallers") || caller.Line == 0 {
+		t.Errorf("caller = %+v, want TestLogger_EnableCallers in caller_test.go", caller)
+	}
+}
+
+func TestLogger_CallersOfInternalEntries(t *testing.T) {
+	l, _ := newTestLogger(t, InfoLvl)
+	var mu sync.Mutex
+	callers := map[string]*Caller{}
+	l.formatter = FormatterFunc(func(entry *Entry) ([]byte, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		callers[entry.Message] = entry.Caller
+		return nil, nil
+	})
+	l.EnableCallers()
+
+	func() {
+		defer l.Recover()
+		panic("boom")
+	}()
+	l.EnableDeduplication(10 * time.Millisecond)
+	defer l.DisableDeduplication()
+	l.Info("down\n")
+	l.Info("down\n")
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		_, flushed := callers["last message repeated 1 times\n"]
+		mu.Unlock()
+		if flushed {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var panicked *Caller
+	for message, caller := range callers {
+		if strings.HasPrefix(message, "panic") {
+			panicked = caller
+		}
+	}
+	if panicked == nil || !strings.HasSuffix(panicked.File, "caller_test.go") {
+		t.Errorf("panic caller = %+v, want the panicking function in caller_test.go", panicked)
+	}
+	if caller, ok := callers["last message repeated 1 times\n"]; !ok || caller != nil {
+		t.Errorf("repeat count caller = %+v (logged %v), want none", caller, ok)
+	}
+}
+
+func TestDebugger_GetCaller(t *testing.T) {
+	l, _ := newTestLogger(t, InfoLvl)
+	caller := l.GetCaller()
+	if !strings.HasSuffix(caller.Function, "TestDebugger_GetCaller") {
+		t.Errorf("GetCaller() = %+v, want TestDebugger_GetCaller", caller)
+	}
+	if got, want := caller.String(), caller.File+":"; !strings.HasPrefix(got, want) {
+		t.Errorf("String() = %q, want the file and line", got)
+	}
+}
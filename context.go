@@ -0,0 +1,185 @@
+package alailog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Field names used by the context helpers.
+const (
+	RequestIDField = "request_id"
+	UserIDField    = "user_id"
+	TraceIDField   = "trace_id"
+	SpanIDField    = "span_id"
+)
+
+// contextKey is the type of the keys alailog stores in a context.Context.
+type contextKey int
+
+const (
+	loggerContextKey contextKey = iota
+	fieldsContextKey
+	traceContextKey
+)
+
+// ContextExtractor returns the fields to attach to the entries logged with ctx.
+// It returns nil when ctx carries nothing of interest.
+type ContextExtractor func(ctx context.Context) Fields
+
+// contextExtractors is a list of extractors safe for concurrent use.
+type contextExtractors struct {
+	mu   sync.RWMutex
+	list []ContextExtractor
+}
+
+// globalExtractors holds the extractors registered with RegisterContextExtractor.
+var globalExtractors contextExtractors
+
+// WithContext returns a copy of ctx carrying the logger, to be retrieved with FromContext.
+func WithContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey, l)
+}
+
+// FromContext returns the logger stored in ctx by WithContext,
+// or the logger instance when ctx carries none.
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(loggerContextKey).(*Logger); ok && l != nil {
+		return l
+	}
+	return GetInstance()
+}
+
+// ContextWithFields returns a copy of ctx carrying the fields in addition to
+// those already in ctx. They are attached to every entry logged with the context.
+func ContextWithFields(ctx context.Context, fields Fields) context.Context {
+	return context.WithValue(ctx, fieldsContextKey, ContextFields(ctx).merge(fields))
+}
+
+// ContextWithRequestID returns a copy of ctx carrying the request ID.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return ContextWithFields(ctx, Fields{RequestIDField: requestID})
+}
+
+// ContextWithUserID returns a copy of ctx carrying the user ID.
+func ContextWithUserID(ctx context.Context, userID string) context.Context {
+	return ContextWithFields(ctx, Fields{UserIDField: userID})
+}
+
+// ContextWithTraceIDs returns a copy of ctx carrying the trace and span IDs.
+func ContextWithTraceIDs(ctx context.Context, traceID, spanID string) context.Context {
+	return ContextWithFields(ctx, Fields{TraceIDField: traceID, SpanIDField: spanID})
+}
+
+// ContextFields returns the fields stored in ctx by ContextWithFields and the other context helpers.
+func ContextFields(ctx context.Context) Fields {
+	fields, _ := ctx.Value(fieldsContextKey).(Fields)
+	return fields
+}
+
+// RegisterContextExtractor adds an extractor used by every logger to pull fields out of contexts,
+// e.g. the IDs stored by another tracing or authentication library.
+func RegisterContextExtractor(extractor ContextExtractor) {
+	globalExtractors.add(extractor)
+}
+
+// AddContextExtractor adds an extractor used by this logger to pull fields out of contexts.
+func (l *Logger) AddContextExtractor(extractor ContextExtractor) {
+	l.extractors.add(extractor)
+}
+
+// LogCtx logs a message at the specified level with the fields carried by ctx.
+// Fields from ContextWithFields come first, then those of the registered extractors,
+// then those of the logger's own extractors; later fields override earlier ones.
+func (l *Logger) LogCtx(ctx context.Context, level Level, messageStr interface{}) {
+	if level < l.level && l.recorder.Load() == nil {
+		return
+	}
+	l.logEntry(&Entry{Level: level, Message: fmt.Sprintf("%s", messageStr), Fields: l.contextFields(ctx), color: l.textColor})
+}
+
+// contextFields returns the fields carried by ctx, or nil when there are none.
+func (l *Logger) contextFields(ctx context.Context) Fields {
+	fields := ContextFields(ctx).merge(globalExtractors.extract(ctx)).merge(l.extractors.extract(ctx))
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// DebugCtx logs a debug message with the fields carried by ctx, when debug mode is on.
+func (l *Logger) DebugCtx(ctx context.Context, message interface{}) {
+	if l.DebugMode {
+		l.LogCtx(ctx, DebugLvl, message)
+	}
+}
+
+// InfoCtx logs an info message with the fields carried by ctx.
+//
+// Example usage:
+//
+//	ctx = alailog.ContextWithRequestID(ctx, "req-42")
+//	logger.InfoCtx(ctx, "order created\n")
+func (l *Logger) InfoCtx(ctx context.Context, message interface{}) {
+	l.LogCtx(ctx, InfoLvl, message)
+}
+
+// WarnCtx logs a warning message with the fields carried by ctx.
+func (l *Logger) WarnCtx(ctx context.Context, message interface{}) {
+	l.LogCtx(ctx, WarnLvl, message)
+}
+
+// ErrorCtx logs an error message with the fields carried by ctx.
+func (l *Logger) ErrorCtx(ctx context.Context, message interface{}) {
+	l.LogCtx(ctx, ErrorLvl, message)
+}
+
+// FatalCtx logs a fatal message with the fields carried by ctx.
+func (l *Logger) FatalCtx(ctx context.Context, message interface{}) {
+	l.LogCtx(ctx, FatalLvl, message)
+}
+
+// DebugCtx logs a debug message with the logger carried by ctx and the fields it holds.
+func DebugCtx(ctx context.Context, args ...interface{}) {
+	FromContext(ctx).DebugCtx(ctx, fmt.Sprint(args...))
+}
+
+// InfoCtx logs an info message with the logger carried by ctx and the fields it holds.
+func InfoCtx(ctx context.Context, args ...interface{}) {
+	FromContext(ctx).InfoCtx(ctx, fmt.Sprint(args...))
+}
+
+// WarnCtx logs a warning message with the logger carried by ctx and the fields it holds.
+func WarnCtx(ctx context.Context, args ...interface{}) {
+	FromContext(ctx).WarnCtx(ctx, fmt.Sprint(args...))
+}
+
+// ErrorCtx logs an error message with the logger carried by ctx and the fields it holds.
+func ErrorCtx(ctx context.Context, args ...interface{}) {
+	FromContext(ctx).ErrorCtx(ctx, fmt.Sprint(args...))
+}
+
+// FatalCtx logs a fatal message with the logger carried by ctx and the fields it holds.
+func FatalCtx(ctx context.Context, args ...interface{}) {
+	FromContext(ctx).FatalCtx(ctx, fmt.Sprint(args...))
+}
+
+// add appends an extractor to the list.
+func (c *contextExtractors) add(extractor ContextExtractor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.list = append(c.list, extractor)
+}
+
+// extract merges the fields returned by every extractor for ctx.
+func (c *contextExtractors) extract(ctx context.Context) Fields {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var fields Fields
+	for _, extractor := range c.list {
+		if extracted := extractor(ctx); len(extracted) > 0 {
+			fields = fields.merge(extracted)
+		}
+	}
+	return fields
+}
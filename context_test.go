@@ -0,0 +1,44 @@
+package alailog
+
+import (
+	"context"
+	"testing"
+)
+
+type tenantKey struct{}
+
+func TestLogger_InfoCtx(t *testing.T) {
+	l, output := newTestLogger(t, InfoLvl)
+	l.AddContextExtractor(func(ctx context.Context) Fields {
+		if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+			return Fields{"tenant": tenant}
+		}
+		return nil
+	})
+
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	ctx = ContextWithUserID(ctx, "u 7")
+	ctx = context.WithValue(ctx, tenantKey{}, "acme")
+	l.InfoCtx(ctx, "created\n")
+	l.InfoCtx(context.Background(), "plain\n")
+	l.DebugCtx(ctx, "hidden\n")
+
+	want := "created request_id=req-1 tenant=acme user_id=\"u 7\"\nplain\n"
+	if got := output(); got != want {
+		t.Errorf("InfoCtx() wrote %q, want %q", got, want)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	l, output := newTestLogger(t, InfoLvl)
+	ctx := WithContext(context.Background(), l)
+	if FromContext(ctx) != l {
+		t.Fatal("FromContext() did not return the logger stored by WithContext")
+	}
+
+	ctx = ContextWithTraceIDs(ctx, "t1", "s1")
+	WarnCtx(ctx, "slow")
+	if got, want := output(), "slow span_id=s1 trace_id=t1"; got != want {
+		t.Errorf("WarnCtx() wrote %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,116 @@
+package alailog
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// deduplicator collapses the entries repeating the last logged one within a window.
+type deduplicator struct {
+	window   time.Duration
+	mu       sync.Mutex
+	last     dedupKey
+	logged   time.Time
+	color    Color
+	repeated int
+	timer    *time.Timer
+	run      uint64
+}
+
+// dedupKey identifies identical entries: same level, message and fields.
+type dedupKey struct {
+	level   Level
+	message string
+	fields  string
+}
+
+// EnableDeduplication collapses identical entries logged within window of each other: the first
+// one is logged, the repeats are counted, and a "last message repeated N times" entry is logged
+// when the window ends or a different entry is logged. A window of 0 uses one second.
+// It replaces the previous window, if any.
+//
+// Example usage:
+//
+//	logger.EnableDeduplication(10 * time.Second)
+//	for {
+//	    if err := ping(); err != nil {
+//	        logger.Error("database unreachable\n") // logged once per 10 seconds
+//	    }
+//	}
+func (l *Logger) EnableDeduplication(window time.Duration) {
+	if window <= 0 {
+		window = time.Second
+	}
+	if old := l.dedup.Swap(&deduplicator{window: window, run: 1}); old != nil {
+		l.flushRepeated(old, 0)
+	}
+}
+
+// DisableDeduplication stops collapsing identical entries, logging the pending repeat count first.
+func (l *Logger) DisableDeduplication() {
+	if old := l.dedup.Swap(nil); old != nil {
+		l.flushRepeated(old, 0)
+	}
+}
+
+// deduplicate reports whether the entry is kept, and returns the repeat count entry
+// to log before it, if the entry ends a run of repeats.
+func (l *Logger) deduplicate(d *deduplicator, entry *Entry) (repeats *Entry, keep bool) {
+	key := dedupKey{level: entry.Level, message: entry.Message, fields: entry.Fields.String()}
+	now := time.Now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if key == d.last && now.Sub(d.logged) < d.window {
+		d.repeated++
+		if d.timer == nil {
+			run := d.run
+			d.timer = time.AfterFunc(d.logged.Add(d.window).Sub(now), func() {
+				l.flushRepeated(d, run)
+			})
+		}
+		return nil, false
+	}
+	repeats = d.takeRepeats()
+	d.last = key
+	d.logged = now
+	d.color = entry.color
+	return repeats, true
+}
+
+// flushRepeated logs the repeat count of the current run, once the window has ended.
+// A run of 0 flushes whatever run is pending.
+func (l *Logger) flushRepeated(d *deduplicator, run uint64) {
+	d.mu.Lock()
+	if run != 0 && run != d.run {
+		d.mu.Unlock()
+		return
+	}
+	repeats := d.takeRepeats()
+	d.last = dedupKey{}
+	d.mu.Unlock()
+	if repeats != nil {
+		l.emit(repeats)
+	}
+}
+
+// takeRepeats ends the current run, returning its repeat count entry if the last entry was repeated.
+// It must be called with d.mu held.
+func (d *deduplicator) takeRepeats() *Entry {
+	d.run++
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	if d.repeated == 0 {
+		return nil
+	}
+	repeats := &Entry{
+		Level:   d.last.level,
+		Message: fmt.Sprintf("last message repeated %d times\n", d.repeated),
+		color:   d.color,
+	}
+	d.repeated = 0
+	return repeats
+}
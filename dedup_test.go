@@ -0,0 +1,85 @@
+package alailog
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogger_EnableDeduplication(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []string
+		want     string
+	}{
+		{
+			name:     "repeats collapsed before a different message",
+			messages: []string{"down\n", "down\n", "down\n", "up\n"},
+			want:     "down\nlast message repeated 2 times\nup\n",
+		},
+		{
+			name:     "alternating messages kept",
+			messages: []string{"a\n", "b\n", "a\n"},
+			want:     "a\nb\na\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, output := newTestLogger(t, InfoLvl)
+			l.EnableDeduplication(time.Hour)
+			defer l.DisableDeduplication()
+			for _, message := range tt.messages {
+				l.Info(message)
+			}
+			if got := output(); got != tt.want {
+				t.Errorf("deduplicated output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogger_DeduplicationWindow(t *testing.T) {
+	l, output := newTestLogger(t, InfoLvl)
+	l.EnableDeduplication(20 * time.Millisecond)
+	defer l.DisableDeduplication()
+
+	l.Error("down\n")
+	l.Error("down\n")
+	l.Error("down\n")
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(output(), "repeated") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	l.Error("down\n")
+	if got, want := output(), "down\nlast message repeated 2 times\ndown\n"; got != want {
+		t.Errorf("deduplicated output = %q, want %q", got, want)
+	}
+
+	l.Error("down\n")
+	l.DisableDeduplication()
+	if got := output(); !strings.HasSuffix(got, "down\nlast message repeated 1 times\n") {
+		t.Errorf("DisableDeduplication() did not flush the repeat count: %q", got)
+	}
+}
+
+func TestLogger_CloseFlushesDeduplication(t *testing.T) {
+	l, output := newTestLogger(t, InfoLvl)
+	sink := &recordingSink{}
+	l.AddSink(sink)
+	l.EnableDeduplication(time.Hour)
+	for i := 0; i < 3; i++ {
+		l.Error("down\n")
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got, want := output(), "down\nlast message repeated 2 times\n"; got != want {
+		t.Errorf("output after Close() = %q, want %q", got, want)
+	}
+	if got := sink.messages; len(got) != 2 || got[1] != "last message repeated 2 times\n" {
+		t.Errorf("sink received %q, want the repeat count", got)
+	}
+	if l.dedup.Load() != nil {
+		t.Error("Close() did not stop deduplicating")
+	}
+}
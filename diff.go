@@ -0,0 +1,253 @@
+package alailog
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Change describes one path whose value differs between two values compared by Diff.
+// Path is relative to the compared values, e.g. ".Address.City", "[2]" or `["key"]`,
+// and is empty when the values themselves differ. Before and After hold the rendered
+// values, or "<missing>" when the path only exists on one side.
+type Change struct {
+	Path   string
+	Before string
+	After  string
+}
+
+// missingValue is rendered for a path that only exists on one side of a diff.
+const missingValue = "<missing>"
+
+// Diff compares two values field by field, descending into structs, pointers, maps,
+// slices and arrays, and returns the paths whose values differ, in a deterministic order.
+func Diff(before, after interface{}) []Change {
+	d := &differ{visited: make(map[visit]bool)}
+	d.diff("", reflect.ValueOf(before), reflect.ValueOf(after))
+	return d.changes
+}
+
+// LogDiff logs the paths that changed between before and after, each as a pair of lines:
+// the old value prefixed by "-" in red and the new value prefixed by "+" in green
+// when the logger is colored.
+//
+// Example usage:
+//
+//	before := *user
+//	user.Rename("alice")
+//	logger.LogDiff("user", before, *user)
+func (l *Logger) LogDiff(name string, before, after interface{}) {
+	changes := Diff(before, after)
+	if len(changes) == 0 {
+		l.Infof("Diff %s: no changes\n", name)
+		return
+	}
+	l.Infof("Diff %s: %d changed\n", name, len(changes))
+	for _, change := range changes {
+		l.InfoColor(Red, fmt.Sprintf("- %s%s: %s\n", name, change.Path, change.Before))
+		l.InfoColor(Green, fmt.Sprintf("+ %s%s: %s\n", name, change.Path, change.After))
+	}
+}
+
+// differ collects the changes found while walking two values.
+type differ struct {
+	changes []Change
+	visited map[visit]bool
+}
+
+// visit identifies a pair of pointers, maps or slices on the path being compared, so that
+// cyclic values are not walked forever.
+type visit struct {
+	typ        reflect.Type
+	a, b       uintptr
+	aLen, bLen int
+}
+
+// enter records the pair a and b on the path being compared and reports whether to walk it.
+// Pairs sharing their data, or already on the path through a cycle, are not walked. The caller
+// removes the returned key from d.visited once the pair is walked.
+func (d *differ) enter(a, b reflect.Value) (visit, bool) {
+	key := visit{typ: a.Type(), a: a.Pointer(), b: b.Pointer()}
+	if a.Kind() == reflect.Slice {
+		key.aLen, key.bLen = a.Len(), b.Len()
+	}
+	if key.a == key.b && key.aLen == key.bLen || d.visited[key] {
+		return key, false
+	}
+	d.visited[key] = true
+	return key, true
+}
+
+// diff compares two values found at the same path.
+func (d *differ) diff(path string, a, b reflect.Value) {
+	if a.IsValid() && a.Kind() == reflect.Interface {
+		a = a.Elem()
+	}
+	if b.IsValid() && b.Kind() == reflect.Interface {
+		b = b.Elem()
+	}
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			d.add(path, formatDiffValue(a), formatDiffValue(b))
+		}
+		return
+	}
+	if a.Type() != b.Type() {
+		d.add(path, formatDiffValue(a), formatDiffValue(b))
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				d.add(path, formatDiffValue(a), formatDiffValue(b))
+			}
+			return
+		}
+		key, ok := d.enter(a, b)
+		if !ok {
+			return
+		}
+		defer delete(d.visited, key)
+		d.diff(path, a.Elem(), b.Elem())
+	case reflect.Struct:
+		if !hasExportedFields(a.Type()) && a.CanInterface() {
+			// opaque values such as time.Time are compared as a whole
+			if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+				d.add(path, formatDiffValue(a), formatDiffValue(b))
+			}
+			return
+		}
+		for i := 0; i < a.NumField(); i++ {
+			d.diff(path+"."+a.Type().Field(i).Name, a.Field(i), b.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		if a.Kind() == reflect.Slice && a.IsNil() != b.IsNil() {
+			d.add(path, formatDiffValue(a), formatDiffValue(b))
+			return
+		}
+		if a.Kind() == reflect.Slice && a.Len() > 0 && b.Len() > 0 {
+			key, ok := d.enter(a, b)
+			if !ok {
+				return
+			}
+			defer delete(d.visited, key)
+		}
+		for i := 0; i < a.Len() || i < b.Len(); i++ {
+			elemPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= a.Len():
+				d.add(elemPath, missingValue, formatDiffValue(b.Index(i)))
+			case i >= b.Len():
+				d.add(elemPath, formatDiffValue(a.Index(i)), missingValue)
+			default:
+				d.diff(elemPath, a.Index(i), b.Index(i))
+			}
+		}
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() {
+			d.add(path, formatDiffValue(a), formatDiffValue(b))
+			return
+		}
+		if !a.IsNil() {
+			key, ok := d.enter(a, b)
+			if !ok {
+				return
+			}
+			defer delete(d.visited, key)
+		}
+		for _, key := range mergedKeys(a, b) {
+			keyPath := path + "[" + formatMapKey(key) + "]"
+			av, bv := a.MapIndex(key), b.MapIndex(key)
+			switch {
+			case !av.IsValid():
+				d.add(keyPath, missingValue, formatDiffValue(bv))
+			case !bv.IsValid():
+				d.add(keyPath, formatDiffValue(av), missingValue)
+			default:
+				d.diff(keyPath, av, bv)
+			}
+		}
+	default:
+		if !equalScalar(a, b) {
+			d.add(path, formatDiffValue(a), formatDiffValue(b))
+		}
+	}
+}
+
+// add records a change.
+func (d *differ) add(path, before, after string) {
+	d.changes = append(d.changes, Change{Path: path, Before: before, After: after})
+}
+
+// equalScalar compares two values of the same non-composite kind.
+func equalScalar(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == b.Float()
+	case reflect.Complex64, reflect.Complex128:
+		return a.Complex() == b.Complex()
+	case reflect.String:
+		return a.String() == b.String()
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return a.Pointer() == b.Pointer()
+	}
+	return false
+}
+
+// hasExportedFields reports whether a struct type has at least one exported field.
+func hasExportedFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
+// mergedKeys returns the union of the keys of two maps, sorted like Dump sorts them.
+func mergedKeys(a, b reflect.Value) []reflect.Value {
+	keys := sortedKeys(a)
+	for _, key := range sortedKeys(b) {
+		if !a.MapIndex(key).IsValid() {
+			keys = append(keys, key)
+		}
+	}
+	sortValues(keys)
+	return keys
+}
+
+// formatMapKey renders a map key inside a diff path.
+func formatMapKey(key reflect.Value) string {
+	if key.Kind() == reflect.Interface {
+		key = key.Elem()
+	}
+	if key.Kind() == reflect.String {
+		return strconv.Quote(key.String())
+	}
+	return fmt.Sprintf("%v", key)
+}
+
+// formatDiffValue renders a value on a single line for a diff.
+func formatDiffValue(v reflect.Value) string {
+	if v.IsValid() && v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return "<nil>"
+	}
+	if v.Kind() == reflect.String {
+		return strconv.Quote(v.String())
+	}
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		return fmt.Sprintf("&%+v", v.Elem())
+	}
+	return fmt.Sprintf("%+v", v)
+}
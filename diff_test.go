@@ -0,0 +1,112 @@
+package alailog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type diffAddress struct {
+	City string
+	Zip  int
+}
+
+type diffUser struct {
+	Name    string
+	Address *diffAddress
+	Tags    []string
+	Attrs   map[string]interface{}
+	Created time.Time
+}
+
+func TestDiff(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	before := diffUser{
+		Name:    "bob",
+		Address: &diffAddress{City: "Paris", Zip: 75001},
+		Tags:    []string{"a", "b"},
+		Attrs:   map[string]interface{}{"age": 30, "role": "dev"},
+		Created: now,
+	}
+	after := diffUser{
+		Name:    "bob",
+		Address: &diffAddress{City: "Lyon", Zip: 75001},
+		Tags:    []string{"a"},
+		Attrs:   map[string]interface{}{"age": 31, "team": "core"},
+		Created: now.Add(time.Hour),
+	}
+
+	want := []Change{
+		{Path: ".Address.City", Before: `"Paris"`, After: `"Lyon"`},
+		{Path: ".Tags[1]", Before: `"b"`, After: missingValue},
+		{Path: `.Attrs["age"]`, Before: "30", After: "31"},
+		{Path: `.Attrs["role"]`, Before: `"dev"`, After: missingValue},
+		{Path: `.Attrs["team"]`, Before: missingValue, After: `"core"`},
+		{Path: ".Created", Before: now.String(), After: now.Add(time.Hour).String()},
+	}
+	if got := Diff(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %+v, want %+v", got, want)
+	}
+	if got := Diff(before, before); len(got) != 0 {
+		t.Errorf("Diff() of equal values = %+v, want none", got)
+	}
+	if got := Diff(1, "1"); len(got) != 1 || got[0].Path != "" {
+		t.Errorf("Diff() of different types = %+v, want one root change", got)
+	}
+}
+
+type diffValue struct{ V int }
+
+type diffShared struct{ A, B *diffValue }
+
+func TestDiff_Cycles(t *testing.T) {
+	mapA := map[string]interface{}{"n": 1}
+	mapA["self"] = mapA
+	mapB := map[string]interface{}{"n": 2}
+	mapB["self"] = mapB
+	sliceA := []interface{}{"x", nil}
+	sliceA[1] = sliceA
+	sliceB := []interface{}{"y", nil}
+	sliceB[1] = sliceB
+	before, after := diffValue{V: 1}, diffValue{V: 2}
+
+	tests := []struct {
+		name          string
+		before, after interface{}
+		want          []Change
+	}{
+		{"map", mapA, mapB, []Change{{Path: `["n"]`, Before: "1", After: "2"}}},
+		{"same map", mapA, mapA, nil},
+		{"slice", sliceA, sliceB, []Change{{Path: "[0]", Before: `"x"`, After: `"y"`}}},
+		{"same slice", sliceA, sliceA, nil},
+		{"shared pointer", diffShared{A: &before, B: &before}, diffShared{A: &after, B: &after}, []Change{
+			{Path: ".A.V", Before: "1", After: "2"},
+			{Path: ".B.V", Before: "1", After: "2"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogger_LogDiff(t *testing.T) {
+	l, output := newTestLogger(t, InfoLvl)
+	l.LogDiff("addr", diffAddress{City: "Paris"}, diffAddress{City: "Lyon"})
+	want := "Diff addr: 1 changed\n- addr.City: \"Paris\"\n+ addr.City: \"Lyon\"\n"
+	if got := output(); got != want {
+		t.Errorf("LogDiff() wrote %q, want %q", got, want)
+	}
+
+	l, output = newTestLogger(t, InfoLvl)
+	l.color = true
+	l.LogDiff("addr", diffAddress{Zip: 1}, diffAddress{Zip: 2})
+	got := output()
+	if !strings.Contains(got, Red.String()+"- addr.Zip: 1\n") || !strings.Contains(got, Green.String()+"+ addr.Zip: 2\n") {
+		t.Errorf("LogDiff() wrote %q, want red and green lines", got)
+	}
+}
@@ -0,0 +1,351 @@
+package alailog
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Default limits used by the dumper when the corresponding DumpConfig field is zero.
+const (
+	DefaultDumpMaxDepth        = 10
+	DefaultDumpMaxLength       = 100
+	DefaultDumpMaxStringLength = 256
+)
+
+// DumpConfig controls how Dump, Sdump and LogVar render values.
+//
+//	MaxDepth: how many levels of nested values are expanded (0 uses DefaultDumpMaxDepth)
+//	MaxLength: how many elements of a slice, array or map are shown (0 uses DefaultDumpMaxLength)
+//	MaxStringLength: how many bytes of a string are shown (0 uses DefaultDumpMaxStringLength)
+//	Colored: whether types, field names and values are highlighted with the Color constants
+type DumpConfig struct {
+	MaxDepth        int
+	MaxLength       int
+	MaxStringLength int
+	Colored         bool
+}
+
+// Dump logs a detailed, multi-line representation of each value at the Info level.
+// Types, struct field names and pointer targets are shown, map keys are sorted,
+// and pointer cycles are detected.
+//
+// Example usage:
+//
+//	logger.Dump(user, request)
+func (l *Logger) Dump(values ...interface{}) {
+	l.Log(InfoLvl, l.Sdump(values...)+"\n")
+}
+
+// Sdump returns the representation Dump would log for the values, using the logger's DumpConfig.
+func (l *Logger) Sdump(values ...interface{}) string {
+	return l.DumpConfig.Sdump(values...)
+}
+
+// Sdump returns a detailed, multi-line representation of each value, one value per line.
+func (c DumpConfig) Sdump(values ...interface{}) string {
+	d := &dumpState{config: c.withDefaults(), visited: make(map[uintptr]bool)}
+	for i, value := range values {
+		if i > 0 {
+			d.buf.WriteByte('\n')
+		}
+		d.dump(reflect.ValueOf(value))
+	}
+	return d.buf.String()
+}
+
+// Sdump returns a detailed representation of the values using the default DumpConfig.
+func Sdump(values ...interface{}) string {
+	return DumpConfig{}.Sdump(values...)
+}
+
+// Dump logs a detailed representation of the values using the logger instance.
+func Dump(values ...interface{}) {
+	logger := GetInstance()
+	logger.Dump(values...)
+}
+
+// withDefaults replaces the zero limits of the config with the package defaults.
+func (c DumpConfig) withDefaults() DumpConfig {
+	if c.MaxDepth <= 0 {
+		c.MaxDepth = DefaultDumpMaxDepth
+	}
+	if c.MaxLength <= 0 {
+		c.MaxLength = DefaultDumpMaxLength
+	}
+	if c.MaxStringLength <= 0 {
+		c.MaxStringLength = DefaultDumpMaxStringLength
+	}
+	return c
+}
+
+// dumpState holds the output and the traversal state of one Sdump call.
+type dumpState struct {
+	config  DumpConfig
+	buf     bytes.Buffer
+	depth   int
+	visited map[uintptr]bool
+}
+
+var (
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+	stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// dump writes the type of the value followed by its contents.
+func (d *dumpState) dump(v reflect.Value) {
+	if !v.IsValid() {
+		d.write(Red, "<nil>")
+		return
+	}
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			d.writeType(v.Type())
+			d.write(Red, "<nil>")
+			return
+		}
+		v = v.Elem()
+	}
+	d.writeType(v.Type())
+	d.dumpValue(v)
+}
+
+// dumpValue writes the contents of the value without its type.
+func (d *dumpState) dumpValue(v reflect.Value) {
+	if d.dumpMethod(v) {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		d.dumpPointer(v)
+	case reflect.Struct:
+		d.dumpStruct(v)
+	case reflect.Slice, reflect.Array:
+		d.dumpList(v)
+	case reflect.Map:
+		d.dumpMap(v)
+	case reflect.Interface:
+		d.dump(v)
+	case reflect.String:
+		s := v.String()
+		if len(s) > d.config.MaxStringLength {
+			d.write(Green, strconv.Quote(s[:d.config.MaxStringLength]))
+			d.write(Red, fmt.Sprintf("... (%d more bytes)", len(s)-d.config.MaxStringLength))
+			return
+		}
+		d.write(Green, strconv.Quote(s))
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		if v.IsNil() {
+			d.write(Red, "<nil>")
+			return
+		}
+		d.write(Green, fmt.Sprintf("%#x", v.Pointer()))
+	default:
+		d.write(Green, fmt.Sprintf("%v", v))
+	}
+}
+
+// dumpMethod writes values implementing error or fmt.Stringer using that method, and reports whether it did.
+func (d *dumpState) dumpMethod(v reflect.Value) (ok bool) {
+	if !v.CanInterface() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return false
+	}
+	if !v.Type().Implements(errorType) && !v.Type().Implements(stringerType) {
+		return false
+	}
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	var s string
+	switch value := v.Interface().(type) {
+	case error:
+		s = value.Error()
+	case fmt.Stringer:
+		s = value.String()
+	}
+	d.write(Green, s)
+	return true
+}
+
+// dumpPointer writes the value a pointer points to, stopping at nil pointers and cycles.
+func (d *dumpState) dumpPointer(v reflect.Value) {
+	if v.IsNil() {
+		d.write(Red, "<nil>")
+		return
+	}
+	if d.visited[v.Pointer()] {
+		d.write(Red, "<cycle>")
+		return
+	}
+	d.visited[v.Pointer()] = true
+	defer delete(d.visited, v.Pointer())
+	d.buf.WriteByte('&')
+	d.dumpValue(v.Elem())
+}
+
+// dumpStruct writes the fields of a struct, one per line.
+func (d *dumpState) dumpStruct(v reflect.Value) {
+	if v.NumField() == 0 {
+		d.buf.WriteString("{}")
+		return
+	}
+	if !d.descend() {
+		return
+	}
+	d.buf.WriteString("{\n")
+	for i := 0; i < v.NumField(); i++ {
+		d.indent()
+		d.write(Yellow, v.Type().Field(i).Name)
+		d.buf.WriteString(": ")
+		d.dump(v.Field(i))
+		d.buf.WriteString(",\n")
+	}
+	d.ascend()
+	d.buf.WriteByte('}')
+}
+
+// dumpList writes the elements of a slice or array, one per line.
+func (d *dumpState) dumpList(v reflect.Value) {
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		d.write(Red, "<nil>")
+		return
+	}
+	fmt.Fprintf(&d.buf, "(len=%d) ", v.Len())
+	if v.Len() == 0 {
+		d.buf.WriteString("[]")
+		return
+	}
+	if !d.descend() {
+		return
+	}
+	d.buf.WriteString("[\n")
+	for i := 0; i < v.Len(); i++ {
+		if i == d.config.MaxLength {
+			d.indent()
+			d.write(Red, fmt.Sprintf("... (%d more)\n", v.Len()-i))
+			break
+		}
+		d.indent()
+		d.dump(v.Index(i))
+		d.buf.WriteString(",\n")
+	}
+	d.ascend()
+	d.buf.WriteByte(']')
+}
+
+// dumpMap writes the entries of a map sorted by key, one per line.
+func (d *dumpState) dumpMap(v reflect.Value) {
+	if v.IsNil() {
+		d.write(Red, "<nil>")
+		return
+	}
+	if d.visited[v.Pointer()] {
+		d.write(Red, "<cycle>")
+		return
+	}
+	fmt.Fprintf(&d.buf, "(len=%d) ", v.Len())
+	if v.Len() == 0 {
+		d.buf.WriteString("{}")
+		return
+	}
+	if !d.descend() {
+		return
+	}
+	d.visited[v.Pointer()] = true
+	defer delete(d.visited, v.Pointer())
+	d.buf.WriteString("{\n")
+	keys := sortedKeys(v)
+	for i, key := range keys {
+		if i == d.config.MaxLength {
+			d.indent()
+			d.write(Red, fmt.Sprintf("... (%d more)\n", len(keys)-i))
+			break
+		}
+		d.indent()
+		d.dump(key)
+		d.buf.WriteString(": ")
+		d.dump(v.MapIndex(key))
+		d.buf.WriteString(",\n")
+	}
+	d.ascend()
+	d.buf.WriteByte('}')
+}
+
+// descend enters a nested value, writing a marker instead when the maximum depth is reached.
+func (d *dumpState) descend() bool {
+	if d.depth >= d.config.MaxDepth {
+		d.write(Red, "<max depth reached>")
+		return false
+	}
+	d.depth++
+	return true
+}
+
+// ascend leaves a nested value and indents its closing bracket.
+func (d *dumpState) ascend() {
+	d.depth--
+	d.indent()
+}
+
+// indent writes the indentation of the current depth.
+func (d *dumpState) indent() {
+	d.buf.WriteString(strings.Repeat("  ", d.depth))
+}
+
+// writeType writes the type of a value in parentheses.
+func (d *dumpState) writeType(t reflect.Type) {
+	d.write(Cyan, "("+t.String()+")")
+	d.buf.WriteByte(' ')
+}
+
+// write writes s, highlighted with the color when the config is colored.
+func (d *dumpState) write(color Color, s string) {
+	if d.config.Colored {
+		d.buf.WriteString(color.String() + s + Reset.String())
+		return
+	}
+	d.buf.WriteString(s)
+}
+
+// sortedKeys returns the keys of a map in a deterministic order: numerically
+// for numbers, lexically for strings and by their printed form otherwise.
+func sortedKeys(v reflect.Value) []reflect.Value {
+	keys := v.MapKeys()
+	sortValues(keys)
+	return keys
+}
+
+// sortValues sorts values of the same type in the order used by sortedKeys.
+func sortValues(values []reflect.Value) {
+	sort.Slice(values, func(i, j int) bool {
+		return lessValue(values[i], values[j])
+	})
+}
+
+// lessValue orders two values of the same type for sortValues.
+func lessValue(a, b reflect.Value) bool {
+	if a.Kind() == reflect.Interface {
+		a = a.Elem()
+	}
+	if b.Kind() == reflect.Interface {
+		b = b.Elem()
+	}
+	if a.IsValid() && b.IsValid() && a.Kind() == b.Kind() {
+		switch a.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return a.Int() < b.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return a.Uint() < b.Uint()
+		case reflect.Float32, reflect.Float64:
+			return a.Float() < b.Float()
+		case reflect.String:
+			return a.String() < b.String()
+		}
+	}
+	return fmt.Sprintf("%v", a) < fmt.Sprintf("%v", b)
+}
@@ -0,0 +1,92 @@
+package alailog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type dumpNode struct {
+	Name     string
+	Tags     []string
+	Attrs    map[string]int
+	Next     *dumpNode
+	internal int
+}
+
+func TestSdump(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{name: "nil", value: nil, want: "<nil>"},
+		{name: "int", value: 42, want: "(int) 42"},
+		{name: "string", value: "hi", want: `(string) "hi"`},
+		{name: "error", value: errors.New("boom"), want: "(*errors.errorString) boom"},
+		{name: "nil slice", value: []int(nil), want: "([]int) <nil>"},
+		{name: "empty map", value: map[string]int{}, want: "(map[string]int) (len=0) {}"},
+		{
+			name:  "sorted map",
+			value: map[int]string{10: "b", 2: "a"},
+			want:  "(map[int]string) (len=2) {\n  (int) 2: (string) \"a\",\n  (int) 10: (string) \"b\",\n}",
+		},
+		{
+			name:  "struct",
+			value: dumpNode{Name: "n", Tags: []string{"x"}, internal: 7},
+			want: "(alailog.dumpNode) {\n" +
+				"  Name: (string) \"n\",\n" +
+				"  Tags: ([]string) (len=1) [\n" +
+				"    (string) \"x\",\n" +
+				"  ],\n" +
+				"  Attrs: (map[string]int) <nil>,\n" +
+				"  Next: (*alailog.dumpNode) <nil>,\n" +
+				"  internal: (int) 7,\n" +
+				"}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sdump(tt.value); got != tt.want {
+				t.Errorf("Sdump() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSdump_Cycle(t *testing.T) {
+	n := &dumpNode{Name: "loop"}
+	n.Next = n
+	got := Sdump(n)
+	if !strings.Contains(got, "Next: (*alailog.dumpNode) <cycle>") {
+		t.Errorf("Sdump() = %q, want the cycle to be detected", got)
+	}
+}
+
+func TestDumpConfig_Limits(t *testing.T) {
+	c := DumpConfig{MaxDepth: 1, MaxLength: 2, MaxStringLength: 3}
+	if got := c.Sdump([]int{1, 2, 3, 4}); !strings.Contains(got, "... (2 more)") {
+		t.Errorf("Sdump() = %q, want the list truncated", got)
+	}
+	if got := c.Sdump("abcdef"); got != `(string) "abc"... (3 more bytes)` {
+		t.Errorf("Sdump() = %q, want the string truncated", got)
+	}
+	if got := c.Sdump([][]int{{1}}); !strings.Contains(got, "([]int) (len=1) <max depth reached>") {
+		t.Errorf("Sdump() = %q, want nesting cut at depth 1", got)
+	}
+}
+
+func TestDumpConfig_Colored(t *testing.T) {
+	c := DumpConfig{Colored: true}
+	if got, want := c.Sdump(1), Cyan.String()+"(int)"+Reset.String()+" "+Green.String()+"1"+Reset.String(); got != want {
+		t.Errorf("Sdump() = %q, want %q", got, want)
+	}
+}
+
+func TestLogger_LogVar(t *testing.T) {
+	l, output := newTestLogger(t, InfoLvl)
+	l.LogVar("count", 3)
+	if got, want := output(), "Variable: count, Value: (int) 3\n"; got != want {
+		t.Errorf("LogVar() wrote %q, want %q", got, want)
+	}
+}
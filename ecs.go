@@ -0,0 +1,87 @@
+package alailog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// ECSVersion is the version of the Elastic Common Schema the entries rendered by ECSFormatter follow.
+const ECSVersion = "8.11.0"
+
+// ecsFields maps the fields set by the context helpers to their ECS names.
+var ecsFields = map[string]string{
+	RequestIDField: "http.request.id",
+	UserIDField:    "user.id",
+	TraceIDField:   "trace.id",
+	SpanIDField:    "span.id",
+}
+
+// ecsReserved holds the keys ECSFormatter sets itself; fields named like them are renamed
+// with a "fields." prefix.
+var ecsReserved = map[string]bool{
+	"@timestamp":           true,
+	"log.level":            true,
+	"message":              true,
+	"ecs.version":          true,
+	"log.origin.file.name": true,
+	"log.origin.file.line": true,
+	"log.origin.function":  true,
+	"error.stack_trace":    true,
+}
+
+// ECSFormatter renders entries as ECS JSON documents, one per line, for Elastic. The level goes
+// to log.level, the caller recorded by EnableCallers to log.origin, the stack trace to
+// error.stack_trace, and the request, user, trace and span IDs to their ECS fields. The other
+// fields are top-level keys, so they should be named after the ECS fields they hold; a field
+// named like one of the keys set by the formatter (@timestamp, log.level, message, ecs.version,
+// log.origin.*, error.stack_trace) is renamed with a "fields." prefix.
+//
+// Example usage:
+//
+//	logger, err := alailog.New(alailog.WithFormatter(alailog.ECSFormatter{}))
+//	logger.EnableCallers()
+//	logger.Info("started\n")
+//	// {"@timestamp":"2024-03-05T14:07:09.250Z","log.level":"info","message":"started","ecs.version":"8.11.0",...}
+type ECSFormatter struct{}
+
+// Format renders the entry as an ECS document followed by a newline. As required by ECS logging,
+// @timestamp, log.level and message come first.
+func (f ECSFormatter) Format(entry *Entry) ([]byte, error) {
+	object := map[string]interface{}{"ecs.version": ECSVersion}
+	for key, value := range entry.Fields {
+		if name, ok := ecsFields[key]; ok {
+			key = name
+		} else if ecsReserved[key] {
+			key = "fields." + key
+		}
+		object[key] = jsonValue(value)
+	}
+	if entry.Caller != nil {
+		object["log.origin.file.name"] = entry.Caller.File
+		object["log.origin.file.line"] = entry.Caller.Line
+		object["log.origin.function"] = entry.Caller.Function
+	}
+	if entry.Stack != "" {
+		object["error.stack_trace"] = entry.Stack
+	}
+	rest, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	head, err := json.Marshal(struct {
+		Timestamp string `json:"@timestamp"`
+		Level     string `json:"log.level"`
+		Message   string `json:"message"`
+	}{
+		Timestamp: entry.Time.UTC().Format("2006-01-02T15:04:05.000Z07:00"),
+		Level:     strings.ToLower(entry.Level.String()),
+		Message:   strings.TrimSuffix(entry.Message, "\n"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	document := append(bytes.TrimSuffix(head, []byte("}")), ',')
+	document = append(document, bytes.TrimPrefix(rest, []byte("{"))...)
+	return append(document, '\n'), nil
+}
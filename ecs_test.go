@@ -0,0 +1,59 @@
+package alailog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestECSFormatter_Format(t *testing.T) {
+	at := time.Date(2024, 3, 5, 14, 7, 9, 250000000, time.UTC)
+	tests := []struct {
+		name  string
+		entry *Entry
+		want  string
+	}{
+		{
+			name:  "message",
+			entry: &Entry{Time: at, Level: InfoLvl, Message: "started\n"},
+			want:  `{"@timestamp":"2024-03-05T14:07:09.250Z","log.level":"info","message":"started","ecs.version":"8.11.0"}` + "\n",
+		},
+		{
+			name: "caller, stack and fields",
+			entry: &Entry{
+				Time:    at,
+				Level:   ErrorLvl,
+				Message: "failed",
+				Fields:  Fields{TraceIDField: "abc", UserIDField: "42", "event.action": "sync"},
+				Caller:  &Caller{Function: "main.run", File: "/app/main.go", Line: 12},
+				Stack:   "main.run()",
+			},
+			want: `{"@timestamp":"2024-03-05T14:07:09.250Z","log.level":"error","message":"failed",` +
+				`"ecs.version":"8.11.0","error.stack_trace":"main.run()","event.action":"sync",` +
+				`"log.origin.file.line":12,"log.origin.file.name":"/app/main.go","log.origin.function":"main.run",` +
+				`"trace.id":"abc","user.id":"42"}` + "\n",
+		},
+		{
+			name: "fields named like the document keys",
+			entry: &Entry{
+				Time:    at,
+				Level:   WarnLvl,
+				Message: "slow",
+				Fields:  Fields{"message": "m", "log.level": "l", "@timestamp": "t", "ecs.version": "v"},
+			},
+			want: `{"@timestamp":"2024-03-05T14:07:09.250Z","log.level":"warn","message":"slow",` +
+				`"ecs.version":"8.11.0","fields.@timestamp":"t","fields.ecs.version":"v",` +
+				`"fields.log.level":"l","fields.message":"m"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ECSFormatter{}.Format(tt.entry)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Format() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package alailog
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fields holds the key/value pairs attached to an entry, such as request or trace IDs.
+type Fields map[string]interface{}
+
+// Entry is a single message passing through a Logger.
+//
+//	Time: when the message was logged
+//	Level: the level the message was logged at
+//	Message: the message as passed to the logger
+//	Fields: the key/value pairs attached to the message, if any
+//	Goroutine: the goroutine ID and label, when the logger includes goroutine IDs
+//	Stack: the stack trace attached to the message, e.g. for a recovered panic
+//	Caller: the code that logged the message, when the logger records callers
+type Entry struct {
+	Time      time.Time
+	Level     Level
+	Message   string
+	Fields    Fields
+	Goroutine string
+	Stack     string
+	Caller    *Caller
+
+	color    Color
+	template string
+}
+
+// Formatter renders an entry as the bytes written to the outputs of a Logger.
+// Formatters must not keep the entry after Format returns.
+type Formatter interface {
+	Format(entry *Entry) ([]byte, error)
+}
+
+// FormatterFunc adapts a function to the Formatter interface.
+type FormatterFunc func(entry *Entry) ([]byte, error)
+
+// Format calls f(entry).
+func (f FormatterFunc) Format(entry *Entry) ([]byte, error) {
+	return f(entry)
+}
+
+// format renders the entry with the logger's formatter, or as text when it has none.
+func (l *Logger) format(entry *Entry) ([]byte, error) {
+	if l.formatter != nil {
+		return l.formatter.Format(entry)
+	}
+	return []byte(l.formatText(entry)), nil
+}
+
+// formatText renders the entry the way it is written to the file, stdout, and stderr:
+// the message prefixed with the timestamp and goroutine, followed by the sorted fields
+// and the stack trace, wrapped in the entry's color when the logger is colored.
+func (l *Logger) formatText(entry *Entry) string {
+	message := entry.Message
+	if len(entry.Fields) > 0 {
+		body := strings.TrimSuffix(message, "\n")
+		message = body + " " + entry.Fields.String() + message[len(body):]
+	}
+	if entry.Stack != "" {
+		message = strings.TrimSuffix(message, "\n") + "\n" + strings.TrimSuffix(entry.Stack, "\n") + "\n"
+	}
+	if entry.Goroutine != "" {
+		message = fmt.Sprintf("[g%s] %s", entry.Goroutine, message)
+	}
+	if l.Timestamps { // Check if timestamps are to be added
+		timestampFormat := l.TimestampFormat
+		if timestampFormat == "" {
+			timestampFormat = "2006-01-02 15:04:05"
+		}
+		message = fmt.Sprintf("[%s] %s", entry.Time.Format(timestampFormat), message)
+	}
+	if l.color {
+		message = l.bgColor.String() + entry.color.String() + message + Reset.String()
+	}
+	return message
+}
+
+// String renders the fields as space-separated key=value pairs sorted by key.
+// Values containing spaces, quotes or equal signs are quoted.
+func (f Fields) String() string {
+	keys := make([]string, 0, len(f))
+	for key := range f {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		value := fmt.Sprintf("%v", f[key])
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			value = strconv.Quote(value)
+		}
+		pairs[i] = key + "=" + value
+	}
+	return strings.Join(pairs, " ")
+}
+
+// merge returns a new Fields holding the pairs of f overridden by those of other.
+func (f Fields) merge(other Fields) Fields {
+	merged := make(Fields, len(f)+len(other))
+	for key, value := range f {
+		merged[key] = value
+	}
+	for key, value := range other {
+		merged[key] = value
+	}
+	return merged
+}
@@ -0,0 +1,72 @@
+package alailog
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrorHandler receives the internal errors of a Logger: log files that cannot be opened,
+// writes that fail and hooks that return errors. It must not log through the same Logger.
+type ErrorHandler func(err error)
+
+// WriteError is reported to the ErrorHandler when writing an entry to an output fails.
+type WriteError struct {
+	Output string
+	Err    error
+}
+
+// Error returns the output and the underlying error.
+func (e *WriteError) Error() string {
+	return fmt.Sprintf("alailog: write to %s failed: %v", e.Output, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *WriteError) Unwrap() error {
+	return e.Err
+}
+
+// errorState holds the error handler of a logger and its failure counters.
+type errorState struct {
+	mu           sync.Mutex
+	handler      ErrorHandler
+	reported     bool
+	failedWrites atomic.Uint64
+}
+
+// SetErrorHandler sets the handler receiving the internal errors of the logger.
+// By default the first error is reported to stderr and later ones are only counted.
+func (l *Logger) SetErrorHandler(handler ErrorHandler) {
+	l.errors.mu.Lock()
+	defer l.errors.mu.Unlock()
+	l.errors.handler = handler
+}
+
+// FailedWrites returns the number of writes to the file, stdout, stderr, writers, or sinks that failed.
+func (l *Logger) FailedWrites() uint64 {
+	return l.errors.failedWrites.Load()
+}
+
+// reportError passes an internal error to the error handler.
+func (l *Logger) reportError(err error) {
+	l.errors.mu.Lock()
+	handler := l.errors.handler
+	if handler == nil {
+		reported := l.errors.reported
+		l.errors.reported = true
+		l.errors.mu.Unlock()
+		if !reported {
+			fmt.Fprintf(os.Stderr, "%v (further errors are not reported)\n", err)
+		}
+		return
+	}
+	l.errors.mu.Unlock()
+	handler(err)
+}
+
+// reportWriteError counts a failed write and reports it to the error handler.
+func (l *Logger) reportWriteError(output string, err error) {
+	l.errors.failedWrites.Add(1)
+	l.reportError(&WriteError{Output: output, Err: err})
+}
@@ -0,0 +1,49 @@
+package alailog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLogger_WriteErrors(t *testing.T) {
+	l, _ := newTestLogger(t, InfoLvl)
+	var reported []error
+	l.SetErrorHandler(func(err error) {
+		reported = append(reported, err)
+	})
+	l.file.Close()
+
+	l.Info("lost")
+	l.Info("lost again")
+
+	if got := l.FailedWrites(); got != 2 {
+		t.Errorf("FailedWrites() = %d, want 2", got)
+	}
+	if len(reported) != 2 {
+		t.Fatalf("reported %d errors, want 2", len(reported))
+	}
+	var writeErr *WriteError
+	if !errors.As(reported[0], &writeErr) || writeErr.Output != l.file.Name() || !errors.Is(reported[0], os.ErrClosed) {
+		t.Errorf("reported %v, want a WriteError for the closed file", reported[0])
+	}
+}
+
+func TestNewLoggerFromParameter(t *testing.T) {
+	p := getDefaultParameter()
+	p.Filename = filepath.Join(t.TempDir(), "missing", "logs.txt")
+	if l, err := NewLoggerFromParameter(p); err == nil || l != nil {
+		t.Errorf("NewLoggerFromParameter() = %v, %v, want an error", l, err)
+	}
+
+	p.Filename = filepath.Join(t.TempDir(), "logs.txt")
+	l, err := NewLoggerFromParameter(p)
+	if err != nil {
+		t.Fatalf("NewLoggerFromParameter() error = %v", err)
+	}
+	defer l.file.Close()
+	if l.file.Name() != p.Filename || l.level != InfoLvl {
+		t.Errorf("NewLoggerFromParameter() = %+v, want a logger configured by the parameter", l)
+	}
+}
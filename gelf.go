@@ -0,0 +1,264 @@
+package alailog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GELFFormatter renders entries as GELF 1.1 messages, the format of Graylog. The first line of the
+// message is the short_message; the whole message, followed by the stack trace, is the
+// full_message when it has more lines or a stack. The level is mapped to the syslog severity and
+// the fields become additional fields, prefixed with an underscore.
+//
+//	Host: the host reported in the messages ("" uses os.Hostname)
+type GELFFormatter struct {
+	Host string
+}
+
+// Format renders the entry as a GELF message followed by a newline.
+func (f GELFFormatter) Format(entry *Entry) ([]byte, error) {
+	host := f.Host
+	if host == "" {
+		host, _ = os.Hostname()
+	}
+	message := strings.TrimSuffix(entry.Message, "\n")
+	short, _, multiline := strings.Cut(message, "\n")
+	object := map[string]interface{}{
+		"version":       "1.1",
+		"host":          host,
+		"short_message": short,
+		"timestamp":     float64(entry.Time.UnixMilli()) / 1000,
+		"level":         syslogSeverity(entry.Level),
+	}
+	if multiline || entry.Stack != "" {
+		object["full_message"] = strings.TrimSuffix(message+"\n"+entry.Stack, "\n")
+	}
+	if entry.Goroutine != "" {
+		object["_goroutine"] = entry.Goroutine
+	}
+	for key, value := range entry.Fields {
+		object[gelfFieldName(key)] = jsonValue(value)
+	}
+	b, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// gelfFieldName returns the name of the additional field holding a field: the key prefixed with
+// an underscore, with the characters GELF does not allow replaced. The reserved _id becomes __id.
+func gelfFieldName(key string) string {
+	key = strings.Map(func(r rune) rune {
+		if r == '_' || r == '.' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, key)
+	if key == "id" {
+		key = "_id"
+	}
+	return "_" + key
+}
+
+// GELFCompression is the compression of the messages a GELFSink sends over UDP.
+type GELFCompression int
+
+// The compressions of GELF messages sent over UDP. Messages sent over TCP are never compressed.
+const (
+	GELFGzip GELFCompression = iota
+	GELFZlib
+	GELFNoCompression
+)
+
+// gelfChunkMagic starts each chunk of a chunked GELF message.
+var gelfChunkMagic = []byte{0x1e, 0x0f}
+
+// gelfMaxChunks is the number of chunks a GELF message can be split into.
+const gelfMaxChunks = 128
+
+// GELFConfig configures a GELFSink.
+//
+//	Network: "udp" or "tcp"
+//	Address: the host:port of the Graylog input
+//	Host: the host reported in the messages ("" uses os.Hostname)
+//	Compression: how UDP messages are compressed (GELFGzip by default)
+//	ChunkSize: the largest UDP datagram; bigger messages are chunked (0 uses 1420 bytes)
+type GELFConfig struct {
+	Network     string
+	Address     string
+	Host        string
+	Compression GELFCompression
+	ChunkSize   int
+}
+
+// GELFSink sends entries to Graylog as GELF messages: over UDP, compressed and chunked when they
+// exceed ChunkSize, or over TCP, delimited by null bytes. TCP connections are reopened when a
+// write fails.
+type GELFSink struct {
+	config    GELFConfig
+	formatter GELFFormatter
+	mu        sync.Mutex
+	conn      net.Conn
+	closed    bool
+}
+
+// NewGELFSink connects to the Graylog input and returns a sink sending entries to it.
+//
+// Example usage:
+//
+//	sink, err := alailog.NewGELFSink(alailog.GELFConfig{Network: "udp", Address: "graylog.example.com:12201"})
+//	if err != nil {
+//	    return err
+//	}
+//	logger.AddSink(sink)
+func NewGELFSink(config GELFConfig) (*GELFSink, error) {
+	if config.Network != "udp" && config.Network != "tcp" {
+		return nil, fmt.Errorf("alailog: NewGELFSink: unsupported network %q", config.Network)
+	}
+	if config.Host == "" {
+		config.Host, _ = os.Hostname()
+	}
+	if config.ChunkSize <= 0 {
+		config.ChunkSize = 1420
+	}
+	s := &GELFSink{config: config, formatter: GELFFormatter{Host: config.Host}}
+	conn, err := net.DialTimeout(config.Network, config.Address, 5*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("alailog: connect to Graylog: %w", err)
+	}
+	s.conn = conn
+	return s, nil
+}
+
+// Write sends the entry as a GELF message.
+func (s *GELFSink) Write(entry *Entry) error {
+	message, err := s.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	message = bytes.TrimSuffix(message, []byte("\n"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errors.New("alailog: GELF sink closed")
+	}
+	if s.config.Network == "tcp" {
+		return s.writeTCP(append(message, 0))
+	}
+	packets, err := s.packets(message)
+	if err != nil {
+		return err
+	}
+	for _, packet := range packets {
+		if _, err := s.conn.Write(packet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the connection to Graylog.
+func (s *GELFSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// String describes the sink in errors.
+func (s *GELFSink) String() string {
+	return fmt.Sprintf("gelf %s://%s", s.config.Network, s.config.Address)
+}
+
+// writeTCP writes the frame, reconnecting and writing it again once if the connection failed.
+func (s *GELFSink) writeTCP(frame []byte) error {
+	if s.conn != nil {
+		if _, err := s.conn.Write(frame); err == nil {
+			return nil
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+	conn, err := net.DialTimeout("tcp", s.config.Address, 5*time.Second)
+	if err != nil {
+		return fmt.Errorf("alailog: connect to Graylog: %w", err)
+	}
+	s.conn = conn
+	_, err = s.conn.Write(frame)
+	return err
+}
+
+// packets compresses the message and splits it into the chunks of at most ChunkSize bytes.
+func (s *GELFSink) packets(message []byte) ([][]byte, error) {
+	message, err := s.compress(message)
+	if err != nil {
+		return nil, err
+	}
+	if len(message) <= s.config.ChunkSize {
+		return [][]byte{message}, nil
+	}
+	const headerSize = 12 // magic, message ID, sequence number and count
+	size := s.config.ChunkSize - headerSize
+	if size <= 0 {
+		return nil, fmt.Errorf("alailog: GELF chunk size %d too small", s.config.ChunkSize)
+	}
+	count := (len(message) + size - 1) / size
+	if count > gelfMaxChunks {
+		return nil, fmt.Errorf("alailog: GELF message of %d bytes needs %d chunks, more than %d", len(message), count, gelfMaxChunks)
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	packets := make([][]byte, 0, count)
+	for i := 0; i < count; i++ {
+		chunk := message[i*size : min((i+1)*size, len(message))]
+		packet := make([]byte, 0, headerSize+len(chunk))
+		packet = append(packet, gelfChunkMagic...)
+		packet = append(packet, id...)
+		packet = append(packet, byte(i), byte(count))
+		packets = append(packets, append(packet, chunk...))
+	}
+	return packets, nil
+}
+
+// compress compresses the message with the configured compression.
+func (s *GELFSink) compress(message []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch s.config.Compression {
+	case GELFGzip:
+		w = gzip.NewWriter(&buf)
+	case GELFZlib:
+		w = zlib.NewWriter(&buf)
+	case GELFNoCompression:
+		return message, nil
+	default:
+		return nil, errors.New("alailog: unknown GELF compression")
+	}
+	if _, err := w.Write(message); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,223 @@
+package alailog
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGELFFormatter_Format(t *testing.T) {
+	at := time.Date(2024, 3, 5, 14, 7, 9, 250000000, time.UTC)
+	tests := []struct {
+		name  string
+		entry *Entry
+		want  map[string]interface{}
+	}{
+		{
+			name:  "short message",
+			entry: &Entry{Time: at, Level: WarnLvl, Message: "disk almost full\n", Fields: Fields{"disk": "sda", "id": 7, "user name": "ana"}},
+			want: map[string]interface{}{
+				"version":       "1.1",
+				"host":          "web1",
+				"short_message": "disk almost full",
+				"timestamp":     1709647629.25,
+				"level":         float64(4),
+				"_disk":         "sda",
+				"__id":          float64(7),
+				"_user_name":    "ana",
+			},
+		},
+		{
+			name:  "stack in full message",
+			entry: &Entry{Time: at, Level: FatalLvl, Message: "panic: boom\n", Stack: "main.main()\n", Goroutine: "1"},
+			want: map[string]interface{}{
+				"version":       "1.1",
+				"host":          "web1",
+				"short_message": "panic: boom",
+				"full_message":  "panic: boom\nmain.main()",
+				"timestamp":     1709647629.25,
+				"level":         float64(2),
+				"_goroutine":    "1",
+			},
+		},
+		{
+			name:  "multiline message",
+			entry: &Entry{Time: at, Level: InfoLvl, Message: "first\nsecond\n"},
+			want: map[string]interface{}{
+				"version":       "1.1",
+				"host":          "web1",
+				"short_message": "first",
+				"full_message":  "first\nsecond",
+				"timestamp":     1709647629.25,
+				"level":         float64(6),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := GELFFormatter{Host: "web1"}.Format(tt.entry)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			var got map[string]interface{}
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatalf("Format() = %s, not JSON: %v", b, err)
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("Format() = %v, want %v", got, tt.want)
+			}
+			for key, value := range tt.want {
+				if got[key] != value {
+					t.Errorf("Format()[%q] = %v, want %v", key, got[key], value)
+				}
+			}
+		})
+	}
+}
+
+// readGELF reassembles and decompresses the GELF message received on conn.
+func readGELF(t *testing.T, conn net.PacketConn) map[string]interface{} {
+	t.Helper()
+	chunks := map[byte][]byte{}
+	var message []byte
+	for message == nil {
+		buf := make([]byte, 65536)
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		packet := buf[:n]
+		if !bytes.HasPrefix(packet, gelfChunkMagic) {
+			message = packet
+			break
+		}
+		chunks[packet[10]] = packet[12:]
+		if count := int(packet[11]); len(chunks) == count {
+			for i := 0; i < count; i++ {
+				message = append(message, chunks[byte(i)]...)
+			}
+		}
+	}
+
+	var r io.Reader = bytes.NewReader(message)
+	switch {
+	case bytes.HasPrefix(message, []byte{0x1f, 0x8b}):
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = zr
+	case message[0] == 0x78:
+		zr, err := zlib.NewReader(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = zr
+	}
+	var object map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&object); err != nil {
+		t.Fatal(err)
+	}
+	return object
+}
+
+func TestGELFSink_UDP(t *testing.T) {
+	tests := []struct {
+		name        string
+		compression GELFCompression
+		message     string
+	}{
+		{name: "gzip", compression: GELFGzip, message: "short"},
+		{name: "zlib", compression: GELFZlib, message: "short"},
+		{name: "uncompressed chunks", compression: GELFNoCompression, message: strings.Repeat("long message ", 300)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			sink, err := NewGELFSink(GELFConfig{Network: "udp", Address: conn.LocalAddr().String(), Host: "web1", Compression: tt.compression, ChunkSize: 512})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sink.Close()
+
+			if err := sink.Write(&Entry{Time: time.Now(), Level: ErrorLvl, Message: tt.message, Fields: Fields{"job": "sync"}}); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			got := readGELF(t, conn)
+			if got["short_message"] != tt.message {
+				t.Errorf("short_message = %v, want %q", got["short_message"], tt.message)
+			}
+			if got["_job"] != "sync" || got["level"] != float64(3) {
+				t.Errorf("message = %v, want _job sync at level 3", got)
+			}
+		})
+	}
+}
+
+func TestGELFSink_packets(t *testing.T) {
+	s := &GELFSink{config: GELFConfig{Compression: GELFNoCompression, ChunkSize: 20}}
+	if _, err := s.packets(bytes.Repeat([]byte("x"), 8*gelfMaxChunks+1)); err == nil {
+		t.Error("packets() of a message needing more than 128 chunks succeeded, want an error")
+	}
+	packets, err := s.packets(bytes.Repeat([]byte("x"), 20))
+	if err != nil || len(packets) != 1 {
+		t.Errorf("packets() of a message fitting one datagram = %d packets, %v, want 1", len(packets), err)
+	}
+}
+
+func TestGELFSink_TCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	frames := make(chan string, 10)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			frame, err := reader.ReadString(0)
+			if err != nil {
+				return
+			}
+			frames <- frame
+		}
+	}()
+
+	sink, err := NewGELFSink(GELFConfig{Network: "tcp", Address: listener.Addr().String(), Host: "web1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	for _, message := range []string{"one", "two"} {
+		if err := sink.Write(&Entry{Time: time.Now(), Level: InfoLvl, Message: message}); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	for _, message := range []string{"one", "two"} {
+		select {
+		case frame := <-frames:
+			if !strings.HasPrefix(frame, "{") || !strings.Contains(frame, `"short_message":"`+message+`"`) || !strings.HasSuffix(frame, "}\x00") {
+				t.Errorf("received frame %q, want the %q message ending with a null byte", frame, message)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("message %q not received", message)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package alailog
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// goroutineLabels maps goroutine IDs to the labels set with LabelGoroutine.
+var goroutineLabels sync.Map
+
+// LabelGoroutine assigns a label to the calling goroutine. The label is shown next to the
+// goroutine ID in entries and debug messages when goroutine IDs are enabled. Goroutine IDs
+// are reused by the runtime, so call the returned function before the goroutine exits.
+//
+// Example usage:
+//
+//	go func() {
+//	    defer alailog.LabelGoroutine("worker-1")()
+//	    ...
+//	}()
+func LabelGoroutine(label string) (unlabel func()) {
+	id := goroutineID()
+	goroutineLabels.Store(id, label)
+	return func() {
+		goroutineLabels.Delete(id)
+	}
+}
+
+// GoroutineLabel returns the label of the calling goroutine, or an empty string when it has none.
+func GoroutineLabel() string {
+	if label, ok := goroutineLabels.Load(goroutineID()); ok {
+		return label.(string)
+	}
+	return ""
+}
+
+// GoroutineID returns the ID of the calling goroutine.
+func GoroutineID() uint64 {
+	return goroutineID()
+}
+
+// EnableGoroutineIDs includes the goroutine ID and label in every entry and debug message.
+func (l *Logger) EnableGoroutineIDs() {
+	l.GoroutineIDs = true
+}
+
+// DisableGoroutineIDs removes the goroutine ID and label from entries and debug messages.
+func (l *Logger) DisableGoroutineIDs() {
+	l.GoroutineIDs = false
+}
+
+// goroutineName returns the ID of the calling goroutine followed by its label, if any.
+func goroutineName() string {
+	id := goroutineID()
+	if label, ok := goroutineLabels.Load(id); ok {
+		return fmt.Sprintf("%d %s", id, label)
+	}
+	return strconv.FormatUint(id, 10)
+}
+
+// goroutineID returns the ID of the calling goroutine, parsed from the header of its stack trace.
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	field := strings.TrimPrefix(string(buf[:n]), "goroutine ")
+	if i := strings.IndexByte(field, ' '); i >= 0 {
+		field = field[:i]
+	}
+	id, _ := strconv.ParseUint(field, 10, 64)
+	return id
+}
@@ -0,0 +1,40 @@
+package alailog
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLabelGoroutine(t *testing.T) {
+	done := make(chan string)
+	go func() {
+		unlabel := LabelGoroutine("worker")
+		label := GoroutineLabel()
+		unlabel()
+		done <- label + "," + GoroutineLabel()
+	}()
+	if got := <-done; got != "worker," {
+		t.Errorf("labels = %q, want %q", got, "worker,")
+	}
+	if GoroutineLabel() != "" {
+		t.Error("GoroutineLabel() returned the label of another goroutine")
+	}
+}
+
+func TestLogger_GoroutineIDs(t *testing.T) {
+	l, output := newTestLogger(t, InfoLvl)
+	l.EnableGoroutineIDs()
+	defer LabelGoroutine("main")()
+	l.Info("hello\n")
+
+	want := fmt.Sprintf("[g%d main] hello\n", GoroutineID())
+	if got := output(); got != want {
+		t.Errorf("Info() wrote %q, want %q", got, want)
+	}
+
+	l.DisableGoroutineIDs()
+	l.Info("bye\n")
+	if got := output(); got != want+"bye\n" {
+		t.Errorf("Info() wrote %q, want no goroutine prefix", got)
+	}
+}
@@ -0,0 +1,172 @@
+package alailog
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Hook is called for the entries logged at one of its levels, e.g. to count errors
+// or send a notification on Fatal entries without parsing the log output.
+//
+//	Levels: the levels of the entries the hook is fired for
+//	Fire: handles one entry; a returned error is reported to the logger's error handler
+type Hook interface {
+	Levels() []Level
+	Fire(Entry) error
+}
+
+// AsyncHookQueueSize is the number of entries an asynchronous hook can fall behind
+// before new entries are dropped for it.
+const AsyncHookQueueSize = 1024
+
+// hookSet holds the hooks of a logger. pending counts the entries queued for the
+// asynchronous hooks and not handled yet, and idle is signaled when it drops to zero.
+type hookSet struct {
+	mu      sync.Mutex
+	list    []*registeredHook
+	pending int
+	idle    sync.Cond
+	closed  bool
+	workers sync.WaitGroup
+}
+
+// registeredHook is a hook with the queue feeding it when it runs asynchronously.
+type registeredHook struct {
+	hook  Hook
+	queue chan Entry
+}
+
+// AddHook registers a hook fired synchronously, before the entry is written.
+func (l *Logger) AddHook(hook Hook) {
+	l.hooks.add(&registeredHook{hook: hook})
+}
+
+// AddAsyncHook registers a hook fired on its own goroutine, so slow hooks do not delay logging.
+// Entries are dropped for the hook, and reported to the error handler, when it falls more than
+// AsyncHookQueueSize entries behind. Use FlushHooks to wait for the queued entries; Close
+// waits for them and stops the goroutine.
+func (l *Logger) AddAsyncHook(hook Hook) {
+	h := &registeredHook{hook: hook, queue: make(chan Entry, AsyncHookQueueSize)}
+	if !l.hooks.add(h) {
+		return
+	}
+	go func() {
+		defer l.hooks.workers.Done()
+		for entry := range h.queue {
+			if err := h.hook.Fire(entry); err != nil {
+				l.reportError(fmt.Errorf("alailog: hook failed: %w", err))
+			}
+			l.hooks.done()
+		}
+	}()
+}
+
+// FlushHooks waits until the asynchronous hooks have handled every queued entry.
+func (l *Logger) FlushHooks() {
+	l.hooks.mu.Lock()
+	defer l.hooks.mu.Unlock()
+	for l.hooks.pending > 0 {
+		l.hooks.cond().Wait()
+	}
+}
+
+// fireHooks fires the hooks registered for the level of the entry. The entry is queued
+// for the asynchronous hooks under the lock, and the synchronous hooks are fired after
+// releasing it, so a hook may register other hooks.
+func (l *Logger) fireHooks(entry *Entry) {
+	var hooks []Hook
+	dropped := 0
+	l.hooks.mu.Lock()
+	for _, h := range l.hooks.list {
+		if !containsLevel(h.hook.Levels(), entry.Level) {
+			continue
+		}
+		if h.queue == nil {
+			hooks = append(hooks, h.hook)
+			continue
+		}
+		if l.hooks.closed {
+			continue
+		}
+		select {
+		case h.queue <- *entry:
+			l.hooks.pending++
+		default:
+			dropped++
+		}
+	}
+	l.hooks.mu.Unlock()
+
+	for i := 0; i < dropped; i++ {
+		l.reportError(fmt.Errorf("alailog: hook queue full, entry dropped"))
+	}
+	for _, hook := range hooks {
+		if err := hook.Fire(*entry); err != nil {
+			l.reportError(fmt.Errorf("alailog: hook failed: %w", err))
+		}
+	}
+}
+
+// add appends a hook to the set and reports whether it was added. Asynchronous hooks
+// are not added once the set is closed.
+func (s *hookSet) add(h *registeredHook) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if h.queue != nil {
+		if s.closed {
+			return false
+		}
+		s.workers.Add(1)
+	}
+	s.list = append(s.list, h)
+	return true
+}
+
+// done records that an asynchronous hook handled a queued entry.
+func (s *hookSet) done() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending--
+	if s.pending == 0 {
+		s.cond().Broadcast()
+	}
+}
+
+// close closes the queues of the asynchronous hooks and waits for their goroutines
+// to handle the queued entries and exit.
+func (s *hookSet) close() {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		for _, h := range s.list {
+			if h.queue != nil {
+				close(h.queue)
+			}
+		}
+	}
+	s.mu.Unlock()
+	s.workers.Wait()
+}
+
+// cond returns the condition signaled when no entry is pending; s.mu must be held.
+func (s *hookSet) cond() *sync.Cond {
+	if s.idle.L == nil {
+		s.idle.L = &s.mu
+	}
+	return &s.idle
+}
+
+// containsLevel reports whether level is present in levels.
+func containsLevel(levels []Level, level Level) bool {
+	for _, lvl := range levels {
+		if lvl == level {
+			return true
+		}
+	}
+	return false
+}
+
+// AllLevels returns every level an entry can be logged at, for hooks interested in all entries.
+func AllLevels() []Level {
+	return []Level{DebugLvl, InfoLvl, WarnLvl, ErrorLvl, FatalLvl}
+}
@@ -0,0 +1,143 @@
+package alailog
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+type countingHook struct {
+	mu      sync.Mutex
+	levels  []Level
+	entries []Entry
+	err     error
+}
+
+func (h *countingHook) Levels() []Level {
+	return h.levels
+}
+
+func (h *countingHook) Fire(entry Entry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries = append(h.entries, entry)
+	return h.err
+}
+
+func (h *countingHook) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.entries)
+}
+
+func TestLogger_AddHook(t *testing.T) {
+	l, _ := newTestLogger(t, InfoLvl)
+	errorsHook := &countingHook{levels: []Level{ErrorLvl, FatalLvl}}
+	allHook := &countingHook{levels: AllLevels()}
+	l.AddHook(errorsHook)
+	l.AddAsyncHook(allHook)
+
+	l.Log(DebugLvl, "below level")
+	l.Info("info")
+	l.Error("error")
+	l.Fatal("fatal")
+	l.FlushHooks()
+
+	if got := errorsHook.count(); got != 2 {
+		t.Errorf("error hook fired %d times, want 2", got)
+	}
+	if got := allHook.count(); got != 3 {
+		t.Errorf("async hook fired %d times, want 3", got)
+	}
+	if errorsHook.entries[0].Message != "error" || errorsHook.entries[0].Level != ErrorLvl {
+		t.Errorf("hook got %+v, want the error entry", errorsHook.entries[0])
+	}
+}
+
+func TestLogger_HookError(t *testing.T) {
+	l, output := newTestLogger(t, InfoLvl)
+	l.AddHook(&countingHook{levels: AllLevels(), err: errors.New("unreachable")})
+	l.Info("still written")
+	if got := output(); got != "still written" {
+		t.Errorf("Info() wrote %q, want the entry despite the hook error", got)
+	}
+}
+
+type registeringHook struct {
+	logger *Logger
+	added  *countingHook
+}
+
+func (h *registeringHook) Levels() []Level {
+	return AllLevels()
+}
+
+func (h *registeringHook) Fire(Entry) error {
+	h.logger.AddHook(h.added)
+	return nil
+}
+
+func TestLogger_HookAddsHook(t *testing.T) {
+	l, _ := newTestLogger(t, InfoLvl)
+	added := &countingHook{levels: AllLevels()}
+	l.AddHook(&registeringHook{logger: l, added: added})
+	l.Info("first")
+	if got := added.count(); got != 0 {
+		t.Errorf("hook added while firing got %d entries, want 0", got)
+	}
+	l.Info("second")
+	if got := added.count(); got != 1 {
+		t.Errorf("hook added while firing got %d entries, want 1", got)
+	}
+}
+
+func TestLogger_FlushHooksConcurrent(t *testing.T) {
+	l, _ := newTestLogger(t, InfoLvl)
+	hook := &countingHook{levels: AllLevels()}
+	l.AddAsyncHook(hook)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				l.Info("entry")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				l.FlushHooks()
+			}
+		}()
+	}
+	wg.Wait()
+	l.FlushHooks()
+	if got := hook.count(); got != 200 {
+		t.Errorf("async hook fired %d times, want 200", got)
+	}
+}
+
+func TestLogger_CloseStopsAsyncHooks(t *testing.T) {
+	l, _ := newTestLogger(t, InfoLvl)
+	hook := &countingHook{levels: AllLevels()}
+	l.AddAsyncHook(hook)
+	l.Info("before close")
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got := hook.count(); got != 1 {
+		t.Errorf("async hook fired %d times before Close returned, want 1", got)
+	}
+
+	l.Info("after close")
+	l.AddAsyncHook(hook)
+	l.FlushHooks()
+	if got := hook.count(); got != 1 {
+		t.Errorf("async hook fired %d times after Close, want 1", got)
+	}
+	if err := l.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+}
@@ -0,0 +1,294 @@
+package alailog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HTTPSinkConfig configures an HTTPSink.
+//
+//	URL: the endpoint the batches are POSTed to
+//	Headers: the headers added to each request, e.g. for authentication (a Content-Type header
+//	replaces the default one)
+//	NDJSON: whether to send one JSON object per line instead of a JSON array
+//	Gzip: whether to compress the request bodies
+//	Formatter: renders each entry as a JSON object (nil uses JSONFormatter)
+//	BatchSize: the number of entries sent at once (0 uses 100)
+//	BatchInterval: how long an entry waits for its batch to fill up (0 uses one second)
+//	MaxRetries: how many times a failed batch is sent again (0 uses 3, negative never retries)
+//	MinBackoff: the delay before the first retry (0 uses 100 milliseconds)
+//	MaxBackoff: the longest delay between retries (0 uses 10 seconds)
+//	QueueSize: the number of entries waiting to be sent before new ones are dropped (0 uses 10000)
+//	Client: the client sending the requests (nil uses a client with a 10 second timeout)
+type HTTPSinkConfig struct {
+	URL           string
+	Headers       http.Header
+	NDJSON        bool
+	Gzip          bool
+	Formatter     Formatter
+	BatchSize     int
+	BatchInterval time.Duration
+	MaxRetries    int
+	MinBackoff    time.Duration
+	MaxBackoff    time.Duration
+	QueueSize     int
+	Client        *http.Client
+}
+
+// HTTPSink POSTs batches of entries to a log aggregator from a background goroutine. A batch is
+// sent when it is full or when its oldest entry waited BatchInterval. Requests failing with a
+// network error, a 429 or a 5xx status are retried with exponential backoff; other failures
+// drop the batch.
+type HTTPSink struct {
+	config    HTTPSinkConfig
+	payload   func(objects [][]byte) []byte
+	queue     chan []byte
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	closed    atomic.Bool
+	dropped   atomic.Uint64
+	errs      asyncError
+}
+
+// NewHTTPSink returns a sink POSTing the entries to config.URL.
+//
+// Example usage:
+//
+//	sink, err := alailog.NewHTTPSink(alailog.HTTPSinkConfig{
+//	    URL:     "https://logs.example.com/ingest",
+//	    Headers: http.Header{"Authorization": {"Bearer " + token}},
+//	    Gzip:    true,
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	logger.AddSink(sink)
+//	defer logger.Close()
+func NewHTTPSink(config HTTPSinkConfig) (*HTTPSink, error) {
+	return newHTTPSink(config, nil)
+}
+
+// newHTTPSink returns an HTTP sink whose request bodies are built by payload from the JSON
+// objects of a batch, or are JSON arrays or NDJSON when payload is nil.
+func newHTTPSink(config HTTPSinkConfig, payload func(objects [][]byte) []byte) (*HTTPSink, error) {
+	if config.URL == "" {
+		return nil, errors.New("alailog: NewHTTPSink: empty URL")
+	}
+	if config.Formatter == nil {
+		config.Formatter = JSONFormatter{}
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.BatchInterval <= 0 {
+		config.BatchInterval = time.Second
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = 3
+	}
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = 100 * time.Millisecond
+	}
+	if config.MaxBackoff < config.MinBackoff {
+		config.MaxBackoff = max(10*time.Second, config.MinBackoff)
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 10000
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	s := &HTTPSink{
+		config:  config,
+		payload: payload,
+		queue:   make(chan []byte, config.QueueSize),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// Write queues the entry for sending. It returns an error if the queue is full, or if sending
+// earlier batches failed since the last call.
+func (s *HTTPSink) Write(entry *Entry) error {
+	if s.closed.Load() {
+		return errors.New("alailog: HTTP sink closed")
+	}
+	object, err := s.config.Formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	select {
+	case s.queue <- bytes.TrimRight(object, "\n"):
+	default:
+		s.dropped.Add(1)
+		return errors.New("alailog: HTTP sink queue full, entry dropped")
+	}
+	return s.errs.take()
+}
+
+// Dropped returns the number of entries that were not delivered.
+func (s *HTTPSink) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close sends the queued entries, then stops the sink. Batches that fail are not retried
+// once Close is called.
+func (s *HTTPSink) Close() error {
+	s.closeOnce.Do(func() {
+		s.closed.Store(true)
+		close(s.stop)
+	})
+	<-s.done
+	return s.errs.take()
+}
+
+// String describes the sink in errors.
+func (s *HTTPSink) String() string {
+	return s.config.URL
+}
+
+// run batches the queued entries and sends them until the sink is closed.
+func (s *HTTPSink) run() {
+	defer close(s.done)
+	var batch [][]byte
+	flush := time.NewTimer(s.config.BatchInterval)
+	flush.Stop()
+	for {
+		select {
+		case object := <-s.queue:
+			if len(batch) == 0 {
+				flush.Reset(s.config.BatchInterval)
+			}
+			batch = append(batch, object)
+			if len(batch) < s.config.BatchSize {
+				continue
+			}
+			stopTimer(flush)
+		case <-flush.C:
+		case <-s.stop:
+			for {
+				select {
+				case object := <-s.queue:
+					batch = append(batch, object)
+					if len(batch) == s.config.BatchSize {
+						s.send(batch)
+						batch = nil
+					}
+				default:
+					s.send(batch)
+					return
+				}
+			}
+		}
+		s.send(batch)
+		batch = nil
+	}
+}
+
+// send POSTs the batch, retrying with backoff while the failure is temporary.
+func (s *HTTPSink) send(batch [][]byte) {
+	if len(batch) == 0 {
+		return
+	}
+	body, err := s.encode(batch)
+	if err != nil {
+		s.dropped.Add(uint64(len(batch)))
+		s.errs.set(fmt.Errorf("alailog: encode batch: %w", err))
+		return
+	}
+	backoff := s.config.MinBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := s.post(body)
+		if err == nil {
+			return
+		}
+		if !retry || attempt >= s.config.MaxRetries || !s.wait(backoff) {
+			s.dropped.Add(uint64(len(batch)))
+			s.errs.set(fmt.Errorf("alailog: send %d entries: %w", len(batch), err))
+			return
+		}
+		backoff = min(2*backoff, s.config.MaxBackoff)
+	}
+}
+
+// wait waits before retrying a batch, and reports false when the sink is closed first.
+func (s *HTTPSink) wait(delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-s.stop:
+		return false
+	}
+}
+
+// post sends one request, reporting whether a failure is worth retrying.
+func (s *HTTPSink) post(body []byte) (retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, s.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	for key, values := range s.config.Headers {
+		req.Header[key] = values
+	}
+	if req.Header.Get("Content-Type") == "" {
+		if s.config.NDJSON {
+			req.Header.Set("Content-Type", "application/x-ndjson")
+		} else {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	}
+	if s.config.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	resp, err := s.config.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("unexpected status %s", resp.Status)
+}
+
+// encode renders the batch as a JSON array or as NDJSON, compressed if configured.
+func (s *HTTPSink) encode(batch [][]byte) ([]byte, error) {
+	var body bytes.Buffer
+	var w io.Writer = &body
+	var zw *gzip.Writer
+	if s.config.Gzip {
+		zw = gzip.NewWriter(&body)
+		w = zw
+	}
+	var payload []byte
+	if s.payload != nil {
+		payload = s.payload(batch)
+	} else if s.config.NDJSON {
+		payload = append(bytes.Join(batch, []byte("\n")), '\n')
+	} else {
+		payload = append(append([]byte("["), bytes.Join(batch, []byte(","))...), ']')
+	}
+	if _, err := w.Write(payload); err != nil {
+		return nil, err
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return body.Bytes(), nil
+}
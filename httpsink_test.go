@@ -0,0 +1,232 @@
+package alailog
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// aggregator records the requests it receives, failing the first ones with the given statuses.
+type aggregator struct {
+	mu       sync.Mutex
+	failures []int
+	requests []*http.Request
+	bodies   []string
+}
+
+func (a *aggregator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body = zr
+	}
+	b, _ := io.ReadAll(body)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.failures) > 0 {
+		w.WriteHeader(a.failures[0])
+		a.failures = a.failures[1:]
+		return
+	}
+	a.requests = append(a.requests, r)
+	a.bodies = append(a.bodies, string(b))
+}
+
+func (a *aggregator) received() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string(nil), a.bodies...)
+}
+
+func TestHTTPSink(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    HTTPSinkConfig
+		failures  []int
+		wantCount int
+		want      []string
+	}{
+		{
+			name:      "JSON array batches",
+			config:    HTTPSinkConfig{BatchSize: 2},
+			wantCount: 2,
+			want:      []string{`[{"message":"one"},{"message":"two"}]`, `[{"message":"three"}]`},
+		},
+		{
+			name:      "gzip NDJSON",
+			config:    HTTPSinkConfig{BatchSize: 3, NDJSON: true, Gzip: true},
+			wantCount: 1,
+			want:      []string{"{\"message\":\"one\"}\n{\"message\":\"two\"}\n{\"message\":\"three\"}\n"},
+		},
+		{
+			name:      "retried on server errors",
+			config:    HTTPSinkConfig{BatchSize: 3, MinBackoff: time.Millisecond},
+			failures:  []int{http.StatusServiceUnavailable, http.StatusTooManyRequests},
+			wantCount: 1,
+			want:      []string{`[{"message":"one"},{"message":"two"},{"message":"three"}]`},
+		},
+		{
+			name:      "dropped on client errors",
+			config:    HTTPSinkConfig{BatchSize: 3, MinBackoff: time.Millisecond},
+			failures:  []int{http.StatusBadRequest},
+			wantCount: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &aggregator{failures: tt.failures}
+			server := httptest.NewServer(a)
+			defer server.Close()
+
+			config := tt.config
+			config.URL = server.URL
+			config.Headers = http.Header{"Authorization": {"Bearer secret"}}
+			config.Formatter = FormatterFunc(func(entry *Entry) ([]byte, error) {
+				return json.Marshal(map[string]string{"message": entry.Message})
+			})
+			sink, err := NewHTTPSink(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, message := range []string{"one", "two", "three"} {
+				sink.Write(&Entry{Level: InfoLvl, Message: message})
+			}
+			if len(tt.failures) > 0 && tt.wantCount > 0 {
+				waitForRequests(t, a, tt.wantCount) // retries stop once the sink is closed
+			}
+			err = sink.Close()
+
+			got := a.received()
+			if len(got) != tt.wantCount {
+				t.Fatalf("aggregator received %d requests %q, want %d", len(got), got, tt.wantCount)
+			}
+			for i, body := range tt.want {
+				if got[i] != body {
+					t.Errorf("request %d body = %q, want %q", i, got[i], body)
+				}
+			}
+			for _, r := range a.requests {
+				if r.Header.Get("Authorization") != "Bearer secret" {
+					t.Errorf("request headers = %v, want the configured Authorization", r.Header)
+				}
+			}
+			if tt.wantCount == 0 && (err == nil || sink.Dropped() != 3) {
+				t.Errorf("Close() error = %v, Dropped() = %d, want the batch reported as dropped", err, sink.Dropped())
+			}
+		})
+	}
+}
+
+func waitForRequests(t *testing.T, a *aggregator, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(a.received()) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("aggregator received %d requests, want %d", len(a.received()), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHTTPSink_ContentType(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers http.Header
+		ndjson  bool
+		want    string
+	}{
+		{"JSON", nil, false, "application/json"},
+		{"NDJSON", nil, true, "application/x-ndjson"},
+		{"configured", http.Header{"Content-Type": {"application/vnd.logs+json"}}, false, "application/vnd.logs+json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &aggregator{}
+			server := httptest.NewServer(a)
+			defer server.Close()
+			sink, err := NewHTTPSink(HTTPSinkConfig{URL: server.URL, Headers: tt.headers, NDJSON: tt.ndjson})
+			if err != nil {
+				t.Fatal(err)
+			}
+			sink.Write(&Entry{Time: time.Now(), Level: InfoLvl, Message: "typed\n"})
+			if err := sink.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if len(a.requests) != 1 {
+				t.Fatalf("aggregator received %d requests, want 1", len(a.requests))
+			}
+			if got := a.requests[0].Header.Get("Content-Type"); got != tt.want {
+				t.Errorf("Content-Type = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPSink_CloseStopsRetries(t *testing.T) {
+	a := &aggregator{failures: []int{http.StatusServiceUnavailable}}
+	server := httptest.NewServer(a)
+	defer server.Close()
+	sink, err := NewHTTPSink(HTTPSinkConfig{URL: server.URL, BatchSize: 1, MinBackoff: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.Write(&Entry{Time: time.Now(), Level: InfoLvl, Message: "unlucky\n"})
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		a.mu.Lock()
+		failed := len(a.failures) == 0
+		a.mu.Unlock()
+		if failed {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("batch not sent")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	closed := make(chan error)
+	go func() { closed <- sink.Close() }()
+	select {
+	case err := <-closed:
+		if err == nil || sink.Dropped() != 1 {
+			t.Errorf("Close() error = %v, Dropped() = %d, want the batch reported as dropped", err, sink.Dropped())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close() waited for the retry backoff")
+	}
+}
+
+func TestHTTPSink_BatchInterval(t *testing.T) {
+	a := &aggregator{}
+	server := httptest.NewServer(a)
+	defer server.Close()
+	sink, err := NewHTTPSink(HTTPSinkConfig{URL: server.URL, BatchInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	sink.Write(&Entry{Time: time.Now(), Level: InfoLvl, Message: "alone\n"})
+	deadline := time.Now().Add(5 * time.Second)
+	for len(a.received()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("batch not sent after the batch interval")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := a.received()[0]; !strings.HasPrefix(got, "[{") || !strings.Contains(got, `"message":"alone"`) {
+		t.Errorf("request body = %q, want a JSON array with the entry", got)
+	}
+}
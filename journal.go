@@ -0,0 +1,215 @@
+package alailog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultJournalSocket is the socket of the native protocol of journald.
+const DefaultJournalSocket = "/run/systemd/journal/socket"
+
+// JournalConfig configures a JournalSink.
+//
+//	SocketPath: the journald socket ("" uses DefaultJournalSocket)
+//	Identifier: the SYSLOG_IDENTIFIER of the entries ("" uses the program name)
+type JournalConfig struct {
+	SocketPath string
+	Identifier string
+}
+
+// JournalSink sends entries to journald over its native protocol. The level of an entry is its
+// PRIORITY, the caller recorded by EnableCallers goes to CODE_FILE, CODE_LINE and CODE_FUNC, and
+// the fields are sent upper-cased, e.g. request_id as REQUEST_ID (a field named like one the sink
+// sets, such as message, is sent as FIELD_MESSAGE). When the socket is absent, such as outside
+// systemd, entries are written to stderr with the <N> priority prefixes journald understands
+// when it captures the output of a service.
+type JournalSink struct {
+	config JournalConfig
+	mu     sync.Mutex
+	conn   net.Conn
+	stderr io.Writer
+}
+
+// NewJournalSink returns a sink sending entries to journald, or to stderr when its socket is absent.
+//
+// Example usage:
+//
+//	sink, err := alailog.NewJournalSink(alailog.JournalConfig{Identifier: "api"})
+//	if err != nil {
+//	    return err
+//	}
+//	logger.EnableCallers()
+//	logger.AddSink(sink)
+func NewJournalSink(config JournalConfig) (*JournalSink, error) {
+	if config.SocketPath == "" {
+		config.SocketPath = DefaultJournalSocket
+	}
+	if config.Identifier == "" {
+		config.Identifier = filepath.Base(os.Args[0])
+	}
+	s := &JournalSink{config: config, stderr: os.Stderr}
+	if _, err := os.Stat(config.SocketPath); err == nil {
+		conn, err := net.Dial("unixgram", config.SocketPath)
+		if err != nil {
+			return nil, fmt.Errorf("alailog: connect to journald: %w", err)
+		}
+		s.conn = conn
+	}
+	return s, nil
+}
+
+// Write sends the entry to journald. If the connection fails, the sink reconnects and sends the
+// entry again once; if journald is gone, the entry and the next ones are written to stderr.
+func (s *JournalSink) Write(entry *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return s.writeStderr(entry)
+	}
+	message := s.format(entry)
+	if _, err := s.conn.Write(message); err == nil {
+		return nil
+	}
+	s.conn.Close()
+	s.conn = nil
+	conn, err := net.Dial("unixgram", s.config.SocketPath)
+	if err != nil {
+		return s.writeStderr(entry)
+	}
+	s.conn = conn
+	_, err = s.conn.Write(message)
+	return err
+}
+
+// Close closes the connection to journald.
+func (s *JournalSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// String describes the sink in errors.
+func (s *JournalSink) String() string {
+	return "journald " + s.config.SocketPath
+}
+
+// format renders the entry as a message of the journald native protocol.
+func (s *JournalSink) format(entry *Entry) []byte {
+	var buf bytes.Buffer
+	writeJournalField(&buf, "MESSAGE", strings.TrimSuffix(entry.Message, "\n"))
+	writeJournalField(&buf, "PRIORITY", strconv.Itoa(syslogSeverity(entry.Level)))
+	writeJournalField(&buf, "SYSLOG_IDENTIFIER", s.config.Identifier)
+	if entry.Caller != nil {
+		writeJournalField(&buf, "CODE_FILE", entry.Caller.File)
+		writeJournalField(&buf, "CODE_LINE", strconv.Itoa(entry.Caller.Line))
+		writeJournalField(&buf, "CODE_FUNC", entry.Caller.Function)
+	}
+	if entry.Stack != "" {
+		writeJournalField(&buf, "STACK", entry.Stack)
+	}
+	if entry.Goroutine != "" {
+		writeJournalField(&buf, "GOROUTINE", entry.Goroutine)
+	}
+	keys := make([]string, 0, len(entry.Fields))
+	for key := range entry.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		writeJournalField(&buf, journalFieldName(key), fmt.Sprintf("%v", entry.Fields[key]))
+	}
+	return buf.Bytes()
+}
+
+// writeStderr writes the entry to stderr, each line prefixed with the priority of the entry.
+func (s *JournalSink) writeStderr(entry *Entry) error {
+	message := strings.TrimSuffix(entry.Message, "\n")
+	if len(entry.Fields) > 0 {
+		message += " " + entry.Fields.String()
+	}
+	if entry.Stack != "" {
+		message += "\n" + strings.TrimSuffix(entry.Stack, "\n")
+	}
+	prefix := fmt.Sprintf("<%d>", syslogSeverity(entry.Level))
+	var buf bytes.Buffer
+	for _, line := range strings.Split(message, "\n") {
+		buf.WriteString(prefix + line + "\n")
+	}
+	_, err := s.stderr.Write(buf.Bytes())
+	return err
+}
+
+// writeJournalField appends a field to a native protocol message: KEY=value on one line, or,
+// for values with newlines, the key, the little-endian 64-bit length of the value and the value.
+func writeJournalField(buf *bytes.Buffer, key, value string) {
+	if !strings.Contains(value, "\n") {
+		buf.WriteString(key + "=" + value + "\n")
+		return
+	}
+	buf.WriteString(key + "\n")
+	binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value + "\n")
+}
+
+// journalReserved holds the journald fields set by JournalSink or interpreted by journald itself;
+// entry fields named like them are prefixed so that they cannot override them.
+var journalReserved = map[string]bool{
+	"MESSAGE":            true,
+	"MESSAGE_ID":         true,
+	"PRIORITY":           true,
+	"SYSLOG_IDENTIFIER":  true,
+	"SYSLOG_FACILITY":    true,
+	"SYSLOG_PID":         true,
+	"SYSLOG_TIMESTAMP":   true,
+	"SYSLOG_RAW":         true,
+	"CODE_FILE":          true,
+	"CODE_LINE":          true,
+	"CODE_FUNC":          true,
+	"STACK":              true,
+	"GOROUTINE":          true,
+	"ERRNO":              true,
+	"TID":                true,
+	"DOCUMENTATION":      true,
+	"INVOCATION_ID":      true,
+	"USER_INVOCATION_ID": true,
+}
+
+// journalFieldName returns the journald name of a field: upper-cased, with the characters other
+// than letters, digits and underscores replaced, and prefixed when it would start with an
+// underscore or a digit, which journald reserves or rejects, or when it is one of the fields set
+// by the sink, and cut to the 64 characters allowed.
+func journalFieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		}
+		return '_'
+	}, key)
+	if name == "" || name[0] == '_' || name[0] >= '0' && name[0] <= '9' {
+		name = "FIELD_" + strings.TrimLeft(name, "_")
+	}
+	if journalReserved[name] {
+		name = "FIELD_" + name
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
@@ -0,0 +1,107 @@
+package alailog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJournalFieldName(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "request_id", want: "REQUEST_ID"},
+		{key: "http.status", want: "HTTP_STATUS"},
+		{key: "_hidden", want: "FIELD_HIDDEN"},
+		{key: "2fa", want: "FIELD_2FA"},
+		{key: "message", want: "FIELD_MESSAGE"},
+		{key: "Priority", want: "FIELD_PRIORITY"},
+		{key: "code.file", want: "FIELD_CODE_FILE"},
+		{key: strings.Repeat("a", 70), want: strings.Repeat("A", 64)},
+	}
+	for _, tt := range tests {
+		if got := journalFieldName(tt.key); got != tt.want {
+			t.Errorf("journalFieldName(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestJournalSink_format(t *testing.T) {
+	s := &JournalSink{config: JournalConfig{Identifier: "api"}}
+	got := s.format(&Entry{
+		Level:   ErrorLvl,
+		Message: "failed\n",
+		Fields:  Fields{"request_id": "r1", "message": "spoof", "priority": "7"},
+		Caller:  &Caller{Function: "main.run", File: "/app/main.go", Line: 12},
+		Stack:   "main.run()\nmain.main()",
+	})
+
+	var want bytes.Buffer
+	want.WriteString("MESSAGE=failed\nPRIORITY=3\nSYSLOG_IDENTIFIER=api\n")
+	want.WriteString("CODE_FILE=/app/main.go\nCODE_LINE=12\nCODE_FUNC=main.run\n")
+	want.WriteString("STACK\n")
+	binary.Write(&want, binary.LittleEndian, uint64(len("main.run()\nmain.main()")))
+	want.WriteString("main.run()\nmain.main()\n")
+	want.WriteString("FIELD_MESSAGE=spoof\nFIELD_PRIORITY=7\nREQUEST_ID=r1\n")
+	if !bytes.Equal(got, want.Bytes()) {
+		t.Errorf("format() = %q, want %q", got, want.Bytes())
+	}
+}
+
+func TestJournalSink_Socket(t *testing.T) {
+	dir, err := os.MkdirTemp("", "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "socket")
+	server, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram sockets unavailable: %v", err)
+	}
+	defer server.Close()
+
+	sink, err := NewJournalSink(JournalConfig{SocketPath: path, Identifier: "api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	l, _ := newTestLogger(t, InfoLvl)
+	l.EnableCallers()
+	l.AddSink(sink)
+	l.Warn("slow\n")
+
+	buf := make([]byte, 4096)
+	server.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := server.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(buf[:n])
+	for _, want := range []string{"MESSAGE=slow\n", "PRIORITY=4\n", "SYSLOG_IDENTIFIER=api\n", "CODE_FILE=", "journal_test.go\n", "CODE_FUNC="} {
+		if !strings.Contains(got, want) {
+			t.Errorf("journald received %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestJournalSink_Stderr(t *testing.T) {
+	sink, err := NewJournalSink(JournalConfig{SocketPath: filepath.Join(t.TempDir(), "absent")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stderr bytes.Buffer
+	sink.stderr = &stderr
+
+	sink.Write(&Entry{Level: InfoLvl, Message: "started\n", Fields: Fields{"port": 8080}})
+	sink.Write(&Entry{Level: FatalLvl, Message: "panic\n", Stack: "main.main()\n"})
+	if got, want := stderr.String(), "<6>started port=8080\n<2>panic\n<2>main.main()\n"; got != want {
+		t.Errorf("stderr = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,65 @@
+package alailog
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// JSONFormatter renders each entry as a JSON object on one line, for log collectors.
+// The fields of the entry are top-level keys; a field named like one of the keys of the
+// entry itself (time, level, message, goroutine, stack) is renamed with a "fields." prefix.
+//
+//	TimestampFormat: the format of the time key ("" uses RFC 3339 with nanoseconds)
+//
+// Example usage:
+//
+//	logger, err := alailog.New(alailog.WithFormatter(alailog.JSONFormatter{}))
+//	logger.InfoCtx(ctx, "started\n")
+//	// {"level":"INFO","message":"started","request_id":"abc123","time":"2024-03-05T14:07:09.123456789Z"}
+type JSONFormatter struct {
+	TimestampFormat string
+}
+
+// Format renders the entry as a JSON object followed by a newline.
+func (f JSONFormatter) Format(entry *Entry) ([]byte, error) {
+	object := make(map[string]interface{}, len(entry.Fields)+5)
+	for key, value := range entry.Fields {
+		switch key {
+		case "time", "level", "message", "goroutine", "stack":
+			key = "fields." + key
+		}
+		object[key] = jsonValue(value)
+	}
+	timestampFormat := f.TimestampFormat
+	if timestampFormat == "" {
+		timestampFormat = time.RFC3339Nano
+	}
+	object["time"] = entry.Time.Format(timestampFormat)
+	object["level"] = entry.Level.String()
+	object["message"] = strings.TrimSuffix(entry.Message, "\n")
+	if entry.Goroutine != "" {
+		object["goroutine"] = entry.Goroutine
+	}
+	if entry.Stack != "" {
+		object["stack"] = entry.Stack
+	}
+	b, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// jsonValue returns a value that encodes to JSON: errors and values that cannot be encoded
+// are replaced with their text.
+func jsonValue(value interface{}) interface{} {
+	if err, ok := value.(error); ok {
+		return err.Error()
+	}
+	if _, err := json.Marshal(value); err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return value
+}
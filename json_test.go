@@ -0,0 +1,50 @@
+package alailog
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJSONFormatter_Format(t *testing.T) {
+	at := time.Date(2024, 3, 5, 14, 7, 9, 0, time.UTC)
+	tests := []struct {
+		name      string
+		formatter JSONFormatter
+		entry     *Entry
+		want      string
+	}{
+		{
+			name:  "message",
+			entry: &Entry{Time: at, Level: InfoLvl, Message: "started\n"},
+			want:  `{"level":"INFO","message":"started","time":"2024-03-05T14:07:09Z"}` + "\n",
+		},
+		{
+			name:      "fields",
+			formatter: JSONFormatter{TimestampFormat: "2006-01-02"},
+			entry: &Entry{Time: at, Level: ErrorLvl, Message: "failed", Fields: Fields{
+				"attempt": 3,
+				"err":     errors.New("timeout"),
+				"level":   "shadowed",
+				"ratio":   complex(1, 2),
+			}},
+			want: `{"attempt":3,"err":"timeout","fields.level":"shadowed","level":"ERROR","message":"failed","ratio":"(1+2i)","time":"2024-03-05"}` + "\n",
+		},
+		{
+			name:  "goroutine and stack",
+			entry: &Entry{Time: at, Level: FatalLvl, Message: "panic: boom\n", Goroutine: "7", Stack: "main.main()\n"},
+			want:  `{"goroutine":"7","level":"FATAL","message":"panic: boom","stack":"main.main()\n","time":"2024-03-05T14:07:09Z"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.formatter.Format(tt.entry)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Format() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
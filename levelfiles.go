@@ -0,0 +1,125 @@
+package alailog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// RotationConfig configures the rotation of log files by size. When a write would make a file
+// larger than MaxSize, the file is renamed with a .1 suffix, the older backups are shifted to
+// .2, .3 and so on, and a new file is started.
+//
+//	MaxSize: the size in bytes a file grows to before it is rotated (0 never rotates)
+//	MaxBackups: the number of rotated files kept (0 keeps 5)
+type RotationConfig struct {
+	MaxSize    int64
+	MaxBackups int
+}
+
+// rotatingFile is a log file opened on the first write and rotated by size.
+type rotatingFile struct {
+	name   string
+	config RotationConfig
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+}
+
+// newRotatingFile returns the file with the given name, rotated as configured.
+func newRotatingFile(name string, config RotationConfig) *rotatingFile {
+	if config.MaxBackups <= 0 {
+		config.MaxBackups = 5
+	}
+	return &rotatingFile{name: name, config: config}
+}
+
+// Write appends p to the file, opening or rotating it first if needed.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file != nil && f.config.MaxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.config.MaxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Name returns the name of the file.
+func (f *rotatingFile) Name() string {
+	return f.name
+}
+
+// Close closes the file. It is reopened by the next write.
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// open opens or creates the file in append mode with permission 0666.
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+// rotate closes the file and shifts it and its backups, dropping the oldest backup.
+// The file is reopened by the write that follows.
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+	for i := f.config.MaxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(f.backup(i), f.backup(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(f.name, f.backup(1))
+}
+
+// backup returns the name of the i-th most recent rotated file.
+func (f *rotatingFile) backup(i int) string {
+	return fmt.Sprintf("%s.%d", f.name, i)
+}
+
+// levelFileName returns the name of the file of one level: the level, lower-cased, inserted
+// before the extension of the combined file name, e.g. logs.error.txt for logs.txt.
+func levelFileName(filename string, level Level) string {
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + "." + strings.ToLower(level.String()) + ext
+}
+
+// newLevelFiles returns the files of each level an entry can be logged at, named after filename.
+// The files are created when the first entry of their level is written.
+func newLevelFiles(filename string, rotation RotationConfig) map[Level]*rotatingFile {
+	files := make(map[Level]*rotatingFile)
+	for _, level := range AllLevels() {
+		files[level] = newRotatingFile(levelFileName(filename, level), rotation)
+	}
+	return files
+}
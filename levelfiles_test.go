@@ -0,0 +1,130 @@
+package alailog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLevelFileName(t *testing.T) {
+	tests := []struct {
+		filename string
+		level    Level
+		want     string
+	}{
+		{filename: "logs.txt", level: ErrorLvl, want: "logs.error.txt"},
+		{filename: "/var/log/api.log", level: InfoLvl, want: "/var/log/api.info.log"},
+		{filename: "logs", level: WarnLvl, want: "logs.warn"},
+	}
+	for _, tt := range tests {
+		if got := levelFileName(tt.filename, tt.level); got != tt.want {
+			t.Errorf("levelFileName(%q, %s) = %q, want %q", tt.filename, tt.level, got, tt.want)
+		}
+	}
+}
+
+// readLogFile returns the contents of a log file, or "<missing>" if it does not exist.
+func readLogFile(t *testing.T, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(name)
+	if os.IsNotExist(err) {
+		return "<missing>"
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestNewLoggerFromParameter_LevelFiles(t *testing.T) {
+	tests := []struct {
+		name           string
+		levelFilesOnly bool
+		wantCombined   string
+	}{
+		{name: "with the combined file", wantCombined: "started\nfailed\nretrying\n"},
+		{name: "level files only", levelFilesOnly: true, wantCombined: "<missing>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "logs.txt")
+			l, err := NewLoggerFromParameter(&Parameter{
+				Filename:       filename,
+				Level:          InfoLvl,
+				LevelFiles:     true,
+				LevelFilesOnly: tt.levelFilesOnly,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer l.Close()
+
+			l.Log(DebugLvl, "below level\n")
+			l.Info("started\n")
+			l.Error("failed\n")
+			l.Warn("retrying\n")
+
+			want := map[string]string{
+				filename:                          tt.wantCombined,
+				levelFileName(filename, DebugLvl): "<missing>",
+				levelFileName(filename, InfoLvl):  "started\n",
+				levelFileName(filename, WarnLvl):  "retrying\n",
+				levelFileName(filename, ErrorLvl): "failed\n",
+				levelFileName(filename, FatalLvl): "<missing>",
+			}
+			for name, contents := range want {
+				if got := readLogFile(t, name); got != contents {
+					t.Errorf("%s = %q, want %q", filepath.Base(name), got, contents)
+				}
+			}
+		})
+	}
+}
+
+func TestNewLoggerFromParameter_Rotation(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "logs.txt")
+	l, err := NewLoggerFromParameter(&Parameter{
+		Filename:   filename,
+		Level:      InfoLvl,
+		LevelFiles: true,
+		Rotation:   RotationConfig{MaxSize: 10, MaxBackups: 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	for _, message := range []string{"error 1\n", "error 2\n", "error 3\n", "error 4\n"} {
+		l.Error(message)
+	}
+	l.Info("info 1\n")
+
+	errors := levelFileName(filename, ErrorLvl)
+	want := map[string]string{
+		errors:                           "error 4\n",
+		errors + ".1":                    "error 3\n",
+		errors + ".2":                    "error 2\n",
+		errors + ".3":                    "<missing>",
+		filename:                         "info 1\n",
+		filename + ".1":                  "error 4\n",
+		levelFileName(filename, InfoLvl): "info 1\n",
+	}
+	for name, contents := range want {
+		if got := readLogFile(t, name); got != contents {
+			t.Errorf("%s = %q, want %q", filepath.Base(name), got, contents)
+		}
+	}
+}
+
+func TestRotatingFile_WriteError(t *testing.T) {
+	l, _ := newTestLogger(t, InfoLvl)
+	var reported []error
+	l.SetErrorHandler(func(err error) { reported = append(reported, err) })
+	l.levelFiles = newLevelFiles(filepath.Join(t.TempDir(), "missing", "logs.txt"), RotationConfig{})
+
+	l.Error("failed\n")
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), "logs.error.txt") {
+		t.Errorf("reported errors = %v, want the level file that cannot be opened", reported)
+	}
+}
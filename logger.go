@@ -2,15 +2,22 @@ package alailog
 
 import (
 	"fmt"
-	"log"
+	"io"
 	"os"
 	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // Debugger is a structure that provides functions for debugging code.
-type Debugger struct{}
+type Debugger struct {
+	// Whether entries and debug messages include the goroutine ID and label
+	GoroutineIDs bool
+	// Whether entries record the function, file and line that logged them
+	Callers bool
+}
 
 // GetFunctionName returns the name of the function that is 'steps' frames up the call stack.
 func (d *Debugger) GetFunctionName(steps int) string {
@@ -41,8 +48,9 @@ func (l *Logger) PrintFileAndLineNumber(steps int) {
 }
 
 // LogVar logs a variable name and the corresponding value.
+// The value is rendered by the dumper configured with the logger's DumpConfig.
 func (l *Logger) LogVar(name string, value interface{}) {
-	Printf("Variable: %s, Value: %v\n", name, value)
+	l.Infof("Variable: %s, Value: %s\n", name, l.Sdump(value))
 }
 
 // ElapsedExecutionTime tracks the execution time of a function or segment of code.
@@ -65,6 +73,10 @@ func (d *Debugger) DebugMessage(goSkip ...int) {
 	}
 	functionName := d.GetFunctionName(skip)
 	file, line := d.GetFileAndLineNumber(skip) // 2 steps back in call stack to account for this function call
+	if d.GoroutineIDs {
+		Printf("Debug Message - Goroutine: %s, Function name: %s, File: %s, Line: %d\n", goroutineName(), functionName, file, line)
+		return
+	}
 	Printf("Debug Message - Function name: %s, File: %s, Line: %d\n", functionName, file, line)
 }
 
@@ -97,6 +109,14 @@ type Parameter struct {
 	BgColor         Color
 	Timestamps      bool
 	TimestampFormat string
+	// Receives the errors of the logger, such as a log file that cannot be opened (nil reports the first error to stderr)
+	ErrorHandler ErrorHandler
+	// Whether each level is also written to its own file, named after Filename, e.g. logs.error.txt
+	LevelFiles bool
+	// Whether only the level files are written, instead of the combined Filename as well
+	LevelFilesOnly bool
+	// The rotation of the log files by size (the zero value never rotates)
+	Rotation RotationConfig
 }
 
 // DefaultFile is a constant that represents the default file name used for logging. By default, it is set to "logs.txt".
@@ -146,25 +166,73 @@ const DefaultFile = "logs.txt"
 // SetStderr(stderr bool) sets whether to log to stderr.
 var loggerInstance GoLogger
 
-// Open or create the log file with write-only permissions, append mode, and permission 0666
+// createInstance creates the logger instance once. If the log file cannot be opened,
+// the error is passed to the parameter's ErrorHandler and the instance logs to the other outputs only.
 func createInstance(p *Parameter) {
+	loggerInstance.doOnce.Do(func() {
+		logger, err := NewLoggerFromParameter(p)
+		if err != nil {
+			logger = newLoggerFromParameter(nil, p)
+			logger.reportError(err)
+		}
+		loggerInstance.Instance = logger
+	})
+}
+
+// NewLoggerFromParameter creates a new Logger configured by the parameter.
+// It opens or creates the log file with write-only permissions, append mode, and permission 0666,
+// and returns an error instead of a Logger if the file cannot be opened. With LevelFiles, the
+// file of each level is created when its first entry is written.
+//
+// Example usage:
+//
+//	logger, err := alailog.NewLoggerFromParameter(&alailog.Parameter{
+//	    Filename:   "logs.txt",
+//	    Level:      alailog.InfoLvl,
+//	    LevelFiles: true, // logs.info.txt, logs.warn.txt, logs.error.txt, ...
+//	    Rotation:   alailog.RotationConfig{MaxSize: 10 << 20, MaxBackups: 3},
+//	})
+func NewLoggerFromParameter(p *Parameter) (*Logger, error) {
+	if p.LevelFiles && p.LevelFilesOnly {
+		return newLoggerFromParameter(nil, p), nil
+	}
+	if p.Rotation.MaxSize > 0 {
+		logger := newLoggerFromParameter(nil, p)
+		combined := newRotatingFile(p.Filename, p.Rotation)
+		if err := combined.open(); err != nil {
+			return nil, fmt.Errorf("alailog: open log file: %w", err)
+		}
+		logger.writers = append(logger.writers, combined)
+		return logger, nil
+	}
 	file, err := os.OpenFile(p.Filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("alailog: open log file: %w", err)
 	}
-	loggerInstance.doOnce.Do(func() {
-		loggerInstance.Instance = NewLogger(
-			file,
-			p.Level,
-			p.Stdout,
-			p.Stderror,
-			p.IsColored,
-			p.BgColor,
-			p.TextColor,
-			p.Timestamps,
-			p.TimestampFormat,
-		)
-	})
+	logger := newLoggerFromParameter(file, p)
+	logger.ownsFile = true
+	return logger, nil
+}
+
+// newLoggerFromParameter creates a Logger writing to file, and to the level files if enabled,
+// configured by the parameter.
+func newLoggerFromParameter(file *os.File, p *Parameter) *Logger {
+	logger := NewLogger(
+		file,
+		p.Level,
+		p.Stdout,
+		p.Stderror,
+		p.IsColored,
+		p.BgColor,
+		p.TextColor,
+		p.Timestamps,
+		p.TimestampFormat,
+	)
+	logger.errors.handler = p.ErrorHandler
+	if p.LevelFiles {
+		logger.levelFiles = newLevelFiles(p.Filename, p.Rotation)
+	}
+	return logger
 }
 
 // initInstance initializes the logger by calling the createInstance function and passing the given parameter.
@@ -222,6 +290,8 @@ func getDefaultParameter() *Parameter {
 type Logger struct {
 	// The file to log to
 	file *os.File
+	// Whether the logger opened file itself, and closes it in Close
+	ownsFile bool
 	// The level to log at
 	level Level
 	// Whether to log to stdout
@@ -230,6 +300,10 @@ type Logger struct {
 	stderr bool
 	// Whether to log in color
 	color bool
+	// Additional outputs to log to
+	writers []io.Writer
+	// How entries are rendered (nil renders them as text)
+	formatter Formatter
 
 	textColor Color
 	bgColor   Color
@@ -239,6 +313,34 @@ type Logger struct {
 
 	DebugMode bool
 
+	// How Dump and LogVar render values
+	DumpConfig DumpConfig
+	// Whether PrintMap logs a map as a single entry instead of one entry per line
+	SingleEntryMaps bool
+
+	// The functions traced by Trace
+	tracer tracer
+	// The statistics of the timers started with StartTimer
+	timers timerRegistry
+	// The extractors pulling fields out of contexts
+	extractors contextExtractors
+	// The hooks fired for logged entries
+	hooks hookSet
+	// The handler of internal errors and the failure counters
+	errors errorState
+	// The sampler limiting repeated entries, if any
+	sampler atomic.Pointer[sampler]
+	// The deduplicator collapsing identical entries, if any
+	dedup atomic.Pointer[deduplicator]
+	// The token buckets limiting the rate of entries, if any
+	limiter atomic.Pointer[rateLimiter]
+	// The flight recorder keeping the last entries, if any
+	recorder atomic.Pointer[flightRecorder]
+	// The sinks receiving the written entries
+	sinks sinkSet
+	// The file of each level, when levels are written to their own files
+	levelFiles map[Level]*rotatingFile
+
 	Debugger
 }
 
@@ -293,6 +395,28 @@ const (
 	OffLvl
 )
 
+// String returns the upper-case name of the level, e.g. "INFO".
+func (lvl Level) String() string {
+	switch lvl {
+	case AllLvl:
+		return "ALL"
+	case DebugLvl:
+		return "DEBUG"
+	case InfoLvl:
+		return "INFO"
+	case WarnLvl:
+		return "WARN"
+	case ErrorLvl:
+		return "ERROR"
+	case FatalLvl:
+		return "FATAL"
+	case OffLvl:
+		return "OFF"
+	default:
+		return fmt.Sprintf("Level(%d)", int(lvl))
+	}
+}
+
 // NewLogger creates a new instance of Logger with the given parameters
 //
 //	file: the log file to write to
@@ -303,6 +427,8 @@ const (
 //	bgColor: background color
 //	textColor: text color
 //	Returns a pointer to the newly created Logger instance
+//
+// New with options such as WithFile, WithLevel and WithColor is easier to read and validates its arguments.
 func NewLogger(file *os.File, level Level, stdout bool, stderr bool, color bool, bgColor Color, textColor Color, timestamps bool, timestampFormat string) *Logger {
 	return &Logger{
 		file:            file,
@@ -334,47 +460,137 @@ func (l *Logger) SetTimestampFormat(format string) {
 }
 
 // Log logs a message at the specified level.
-// If the logger is configured to log in color, it calls the LogColor method with the specified level, text color, and message.
-// Otherwise, it appends a newline character to the message and checks if the level is greater than or equal to the logger's configured level.
+// If the logger is configured to log in color, the message is wrapped in the logger's text and background colors.
+// It checks if the level is greater than or equal to the logger's configured level.
 // If it is, it writes the message to the configured file, stdout, and stderr.
 // If the level is lower than the configured level, it returns without writing the message.
 func (l *Logger) Log(level Level, messageStr interface{}) {
-	var message = fmt.Sprintf("%s", messageStr)
-	if l.Timestamps { // Check if timestamps are to be added
-		timestampFormat := l.TimestampFormat
-		if timestampFormat == "" {
-			timestampFormat = "2006-01-02 15:04:05"
+	l.log(level, l.textColor, fmt.Sprintf("%s", messageStr))
+}
+
+// LogColor logs a message with a specified color. If the logger is configured to log in color, the message will be displayed with the specified color and background. Otherwise, the
+// message is logged as plain text.
+func (l *Logger) LogColor(level Level, color Color, messageStr interface{}) {
+	l.log(level, color, fmt.Sprintf("%s", messageStr))
+}
+
+// log builds an entry for the message and logs it.
+func (l *Logger) log(level Level, color Color, message string) {
+	l.logEntry(&Entry{Level: level, Message: message, color: color})
+}
+
+// logf formats the message and logs it, keeping the format as the template of the entry.
+func (l *Logger) logf(level Level, format string, args []interface{}) {
+	l.logEntry(&Entry{Level: level, Message: fmt.Sprintf(format, args...), template: format, color: l.textColor})
+}
+
+// logEntry logs the entry if its level is enabled and the sampler, the deduplicator and the
+// rate limit keep it, preceded by the entries reporting what they dropped before it and,
+// for errors, by the entries kept by the flight recorder.
+func (l *Logger) logEntry(entry *Entry) {
+	recorder := l.recorder.Load()
+	if entry.Level < l.level {
+		if recorder != nil {
+			l.stamp(entry)
+			recorder.record(entry)
 		}
-		message = fmt.Sprintf("[%s] %s", time.Now().Format(timestampFormat), message)
+		return
 	}
-
-	if l.color {
-		l.LogColor(level, l.textColor, message)
-	} else {
-		if level >= l.level {
-			if l.file != nil {
-				l.file.WriteString(message)
-			}
-			if l.stdout {
-				os.Stdout.WriteString(message)
-			}
-			if l.stderr {
-				os.Stderr.WriteString(message)
-			}
-		} else {
+	if s := l.sampler.Load(); s != nil && !s.sample(entry) {
+		return
+	}
+	if d := l.dedup.Load(); d != nil {
+		repeats, keep := l.deduplicate(d, entry)
+		if repeats != nil {
+			l.emit(repeats)
+		}
+		if !keep {
 			return
 		}
 	}
+	if r := l.limiter.Load(); r != nil {
+		drops, keep := l.rateLimit(r, entry)
+		if !keep {
+			return
+		}
+		if drops != nil {
+			l.emit(drops)
+		}
+	}
+	if recorder != nil {
+		recorded := recorder.recordWritten(entry)
+		for i := range recorded {
+			l.emit(&recorded[i])
+		}
+	}
+	l.emit(entry)
 }
 
-// LogColor logs a message with a specified color. If the logger is configured to log in color, the message will be displayed with the specified color and background. Otherwise, the
-func (l *Logger) LogColor(level Level, color Color, messageStr interface{}) {
-	var message = fmt.Sprintf("%s", messageStr)
-	if l.color {
-		l.Log(level, l.bgColor.String()+color.String()+message+Reset.String())
-	} else {
-		l.Log(level, message)
+// emit stamps the entry, fires the hooks and writes it to the configured outputs and sinks.
+func (l *Logger) emit(entry *Entry) {
+	l.stamp(entry)
+	l.fireHooks(entry)
+	l.write(entry)
+	l.writeSinks(entry)
+}
+
+// stamp sets the time of the entry and, when enabled, its goroutine and caller, unless the entry
+// was already stamped when the flight recorder kept it.
+func (l *Logger) stamp(entry *Entry) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	if l.GoroutineIDs && entry.Goroutine == "" {
+		entry.Goroutine = goroutineName()
+	}
+	if l.Callers && entry.Caller == nil {
+		if caller, ok := externalCaller(); ok {
+			entry.Caller = &caller
+		}
+	}
+}
+
+// write formats the entry and writes it to the configured file, stdout, stderr, writers, and level files.
+// Failed writes are counted and reported to the error handler.
+func (l *Logger) write(entry *Entry) {
+	message, err := l.format(entry)
+	if err != nil {
+		l.reportError(fmt.Errorf("alailog: format entry: %w", err))
+		return
 	}
+	if l.file != nil {
+		if _, err := l.file.Write(message); err != nil {
+			l.reportWriteError(l.file.Name(), err)
+		}
+	}
+	if l.stdout {
+		if _, err := os.Stdout.Write(message); err != nil {
+			l.reportWriteError("stdout", err)
+		}
+	}
+	if l.stderr {
+		if _, err := os.Stderr.Write(message); err != nil {
+			l.reportWriteError("stderr", err)
+		}
+	}
+	for _, w := range l.writers {
+		if _, err := w.Write(message); err != nil {
+			l.reportWriteError(writerName(w), err)
+		}
+	}
+	if file := l.levelFiles[entry.Level]; file != nil {
+		if _, err := file.Write(message); err != nil {
+			l.reportWriteError(file.Name(), err)
+		}
+	}
+}
+
+// writerName describes a writer in errors: its file name if it has one, or its type.
+func writerName(w io.Writer) string {
+	if named, ok := w.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	return fmt.Sprintf("%T", w)
 }
 
 func (l *Logger) DebugLog(skip ...int) (is bool) {
@@ -402,13 +618,23 @@ func (l *Logger) Info(message interface{}) {
 }
 
 func (l *Logger) Logf(format string, args ...interface{}) {
-	l.Log(InfoLvl, fmt.Sprintf(format, args...))
+	l.logf(InfoLvl, format, args)
 }
 
-// PrintMap
+// PrintMap logs the entries of the map at the Info level, sorted by key.
+// Nested maps and struct values are rendered as indented trees below their key.
+// By default every line is logged as its own entry; when SingleEntryMaps is set,
+// the whole map is logged as one multi-line entry.
 func (l *Logger) PrintMap(m map[string]interface{}) {
-	for k, v := range m {
-		l.Log(InfoLvl, fmt.Sprintf("%s: %v", k, v))
+	tree := SprintMap(m)
+	if l.SingleEntryMaps {
+		l.Log(InfoLvl, tree)
+		return
+	}
+	for _, line := range strings.SplitAfter(tree, "\n") {
+		if line != "" {
+			l.Log(InfoLvl, line)
+		}
 	}
 }
 
@@ -590,7 +816,7 @@ func (l *Logger) SetStderr(stderr bool) {
 // The formatted message is logged using the Info method of the logger.
 // Example usage: logger.Infof("Received %d bytes", size)
 func (l *Logger) Infof(format string, args ...interface{}) {
-	l.Info(fmt.Sprintf(format, args...))
+	l.logf(InfoLvl, format, args)
 }
 
 // Debugf formats a message according to the given format specifier and
@@ -609,7 +835,7 @@ func (l *Logger) Debugf(format string, args ...interface{}) {
 // which allows passing any number of arguments of any type.
 // Example usage: logger.Printf("Value of x is %d and y is %f", x, y)
 func (l *Logger) Printf(format string, args ...interface{}) {
-	l.Infof(format, args)
+	l.Infof(format, args...)
 }
 
 // Errorf formats and logs an error message with the specified format and arguments.
@@ -618,11 +844,11 @@ func (l *Logger) Printf(format string, args ...interface{}) {
 // The args parameter is a variadic argument that represents the values to be formatted according to the format string.
 // Example usage: logger.Errorf("Something went wrong
 func (l *Logger) Errorf(format string, args ...interface{}) {
-	l.Error(fmt.Sprintf(format, args...))
+	l.logf(ErrorLvl, format, args)
 }
 
 func (l *Logger) Warningf(format string, args ...interface{}) {
-	l.Warn(fmt.Sprintf(format, args...))
+	l.logf(WarnLvl, format, args)
 }
 
 // Fatalf formats the given string according to the specified format and arguments,
@@ -638,7 +864,7 @@ func (l *Logger) Warningf(format string, args ...interface{}) {
 // The above code will log the formatted error message as a fatal error,
 // causing the program to exit immediately.
 func (l *Logger) Fatalf(format string, args ...interface{}) {
-	l.Fatal(fmt.Sprintf(format, args...))
+	l.logf(FatalLvl, format, args)
 }
 
 func (l *Logger) Infoln(args ...interface{}) {
@@ -591,7 +591,7 @@ func TestNewLogger(t *testing.T) {
 				stderr: true,
 				color:  true,
 			},
-			want: NewLogger(nil, DebugLvl, true, true, true),
+			want: NewLogger(nil, DebugLvl, true, true, true, "", "", false, ""),
 		},
 		{
 			name: "Test",
@@ -608,14 +608,35 @@ func TestNewLogger(t *testing.T) {
 				stdout: true,
 				stderr: true,
 				color:  true,
+
+				DebugMode: true,
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := NewLogger(tt.args.file, tt.args.level, tt.args.stdout, tt.args.stderr, tt.args.color); !reflect.DeepEqual(got, tt.want) {
+			if got := NewLogger(tt.args.file, tt.args.level, tt.args.stdout, tt.args.stderr, tt.args.color, "", "", false, ""); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("NewLogger() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+// newTestLogger returns a Logger writing plain messages to a temporary file and
+// a function that returns everything written so far.
+func newTestLogger(t *testing.T, level Level) (*Logger, func() string) {
+	t.Helper()
+	file, err := os.CreateTemp(t.TempDir(), "logs-*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+	l := NewLogger(file, level, false, false, false, "", "", false, "")
+	return l, func() string {
+		b, err := os.ReadFile(file.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+}
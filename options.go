@@ -0,0 +1,182 @@
+package alailog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Option configures a Logger created by New.
+type Option func(l *Logger) error
+
+// New creates a Logger configured by the options. Without options it logs to stdout at the
+// Info level, with timestamps. It returns an error if an option is invalid or the log file
+// cannot be opened.
+//
+// Example usage:
+//
+//	logger, err := alailog.New(
+//	    alailog.WithFile("logs.txt"),
+//	    alailog.WithLevel(alailog.DebugLvl),
+//	    alailog.WithColor(alailog.Green),
+//	)
+func New(opts ...Option) (*Logger, error) {
+	l := &Logger{
+		level:     InfoLvl,
+		stdout:    true,
+		textColor: White,
+		bgColor:   BgBlack,
+
+		Timestamps:      true,
+		TimestampFormat: "2006/01/02 15:04:05",
+		DebugMode:       true,
+	}
+	for _, opt := range opts {
+		if err := opt(l); err != nil {
+			if l.file != nil {
+				l.file.Close()
+			}
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// WithFile logs to the named file, opened or created in append mode with permission 0666.
+func WithFile(filename string) Option {
+	return func(l *Logger) error {
+		if filename == "" {
+			return errors.New("alailog: WithFile: empty file name")
+		}
+		file, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+		if err != nil {
+			return fmt.Errorf("alailog: open log file: %w", err)
+		}
+		if l.file != nil {
+			l.file.Close()
+		}
+		l.file = file
+		l.ownsFile = true
+		return nil
+	}
+}
+
+// WithLevel sets the level to log at.
+func WithLevel(level Level) Option {
+	return func(l *Logger) error {
+		if level < AllLvl || level > OffLvl {
+			return fmt.Errorf("alailog: WithLevel: invalid level %d", level)
+		}
+		l.level = level
+		return nil
+	}
+}
+
+// WithWriter adds an output the entries are written to, in addition to the file, stdout, and stderr.
+func WithWriter(w io.Writer) Option {
+	return func(l *Logger) error {
+		if w == nil {
+			return errors.New("alailog: WithWriter: nil writer")
+		}
+		l.writers = append(l.writers, w)
+		return nil
+	}
+}
+
+// WithStdout sets whether to log to stdout.
+func WithStdout(stdout bool) Option {
+	return func(l *Logger) error {
+		l.stdout = stdout
+		return nil
+	}
+}
+
+// WithStderr sets whether to log to stderr.
+func WithStderr(stderr bool) Option {
+	return func(l *Logger) error {
+		l.stderr = stderr
+		return nil
+	}
+}
+
+// WithColor enables colored output with the given text color, one of Black, Red, Green,
+// Yellow, Blue, Magenta, Cyan, Purple or White.
+func WithColor(textColor Color) Option {
+	return func(l *Logger) error {
+		if !isTextColor(textColor) {
+			return fmt.Errorf("alailog: WithColor: %q is not a text color", textColor)
+		}
+		l.color = true
+		l.textColor = textColor
+		return nil
+	}
+}
+
+// WithBgColor sets the background color of colored output, one of the Bg colors such as BgBlack.
+func WithBgColor(bgColor Color) Option {
+	return func(l *Logger) error {
+		if !isBgColor(bgColor) {
+			return fmt.Errorf("alailog: WithBgColor: %q is not a background color", bgColor)
+		}
+		l.bgColor = bgColor
+		return nil
+	}
+}
+
+// WithFormatter sets how entries are rendered, replacing the default text format.
+func WithFormatter(formatter Formatter) Option {
+	return func(l *Logger) error {
+		if formatter == nil {
+			return errors.New("alailog: WithFormatter: nil formatter")
+		}
+		l.formatter = formatter
+		return nil
+	}
+}
+
+// WithTimestamps sets whether entries are prefixed with a timestamp, and its format
+// (an empty format keeps the current one).
+func WithTimestamps(timestamps bool, format string) Option {
+	return func(l *Logger) error {
+		l.Timestamps = timestamps
+		if format != "" {
+			l.TimestampFormat = format
+		}
+		return nil
+	}
+}
+
+// WithDebugMode sets whether debug logs are output.
+func WithDebugMode(debugMode bool) Option {
+	return func(l *Logger) error {
+		l.DebugMode = debugMode
+		return nil
+	}
+}
+
+// WithErrorHandler sets the handler receiving the internal errors of the logger.
+func WithErrorHandler(handler ErrorHandler) Option {
+	return func(l *Logger) error {
+		l.errors.handler = handler
+		return nil
+	}
+}
+
+// isTextColor reports whether c is one of the text color constants.
+func isTextColor(c Color) bool {
+	switch c {
+	case Black, Red, Green, Yellow, Blue, Magenta, Cyan, White:
+		return true
+	}
+	return false
+}
+
+// isBgColor reports whether c is one of the background color constants.
+func isBgColor(c Color) bool {
+	switch c {
+	case BgBlack, BgRed, BgGreen, BgYellow, BgBlue, BgMagenta, BgCyan, BgWhite:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package alailog
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "logs.txt")
+	var buf bytes.Buffer
+	l, err := New(
+		WithFile(filename),
+		WithLevel(WarnLvl),
+		WithStdout(false),
+		WithWriter(&buf),
+		WithTimestamps(false, ""),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer l.file.Close()
+
+	l.Info("skipped\n")
+	l.Warn("kept\n")
+	if got := buf.String(); got != "kept\n" {
+		t.Errorf("writer got %q, want %q", got, "kept\n")
+	}
+	if b, _ := os.ReadFile(filename); string(b) != "kept\n" {
+		t.Errorf("file got %q, want %q", b, "kept\n")
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Option
+		want string
+	}{
+		{name: "level", opt: WithLevel(Level(42)), want: "invalid level"},
+		{name: "swapped colors", opt: WithColor(BgRed), want: "not a text color"},
+		{name: "background color", opt: WithBgColor(Red), want: "not a background color"},
+		{name: "nil writer", opt: WithWriter(nil), want: "nil writer"},
+		{name: "nil formatter", opt: WithFormatter(nil), want: "nil formatter"},
+		{name: "missing directory", opt: WithFile(filepath.Join(t.TempDir(), "missing", "logs.txt")), want: "open log file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := New(tt.opt)
+			if err == nil || !strings.Contains(err.Error(), tt.want) || l != nil {
+				t.Errorf("New() = %v, %v, want error containing %q", l, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(WithStdout(false), WithWriter(&buf), WithFormatter(FormatterFunc(func(entry *Entry) ([]byte, error) {
+		return []byte(strings.ToUpper(entry.Message) + "|"), nil
+	})))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	l.Info("a")
+	l.Error("b")
+	if got := buf.String(); got != "A|B|" {
+		t.Errorf("formatted output = %q, want %q", got, "A|B|")
+	}
+}
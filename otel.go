@@ -0,0 +1,109 @@
+package alailog
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// otelFields maps the fields set by the context helpers to their OpenTelemetry attribute names.
+var otelFields = map[string]string{
+	RequestIDField: "http.request.id",
+	UserIDField:    "user.id",
+}
+
+// OTelFormatter renders entries as OpenTelemetry log records, one JSON object per line, following
+// the field names of the log data model: Timestamp, SeverityNumber, SeverityText, Body,
+// Attributes, TraceId and SpanId. The caller recorded by EnableCallers and the stack trace become
+// the code.* and exception.stacktrace attributes.
+//
+//	Resource: the attributes of the entity producing the logs, e.g. service.name (nil omits it)
+//
+// Example usage:
+//
+//	logger, err := alailog.New(alailog.WithFormatter(alailog.OTelFormatter{
+//	    Resource: map[string]interface{}{"service.name": "api"},
+//	}))
+type OTelFormatter struct {
+	Resource map[string]interface{}
+}
+
+// otelRecord is a log record of the OpenTelemetry log data model.
+type otelRecord struct {
+	Timestamp      string                 `json:"Timestamp"`
+	SeverityNumber int                    `json:"SeverityNumber"`
+	SeverityText   string                 `json:"SeverityText"`
+	Body           string                 `json:"Body"`
+	Attributes     map[string]interface{} `json:"Attributes,omitempty"`
+	Resource       map[string]interface{} `json:"Resource,omitempty"`
+	TraceId        string                 `json:"TraceId,omitempty"`
+	SpanId         string                 `json:"SpanId,omitempty"`
+}
+
+// Format renders the entry as a log record followed by a newline.
+func (f OTelFormatter) Format(entry *Entry) ([]byte, error) {
+	record := otelRecord{
+		Timestamp:      strconv.FormatInt(entry.Time.UnixNano(), 10),
+		SeverityNumber: otelSeverity(entry.Level),
+		SeverityText:   entry.Level.String(),
+		Body:           strings.TrimSuffix(entry.Message, "\n"),
+		Attributes:     otelAttributes(entry),
+		Resource:       f.Resource,
+	}
+	if id, ok := entry.Fields[TraceIDField].(string); ok {
+		record.TraceId = id
+	}
+	if id, ok := entry.Fields[SpanIDField].(string); ok {
+		record.SpanId = id
+	}
+	b, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// otelAttributes returns the attributes of the log record of the entry: its fields, other than the
+// trace and span IDs, and its caller and stack trace, named after the semantic conventions.
+func otelAttributes(entry *Entry) map[string]interface{} {
+	attributes := make(map[string]interface{}, len(entry.Fields)+4)
+	for key, value := range entry.Fields {
+		switch key {
+		case TraceIDField, SpanIDField:
+			continue
+		}
+		if name, ok := otelFields[key]; ok {
+			key = name
+		}
+		attributes[key] = jsonValue(value)
+	}
+	if entry.Caller != nil {
+		attributes["code.function.name"] = entry.Caller.Function
+		attributes["code.file.path"] = entry.Caller.File
+		attributes["code.line.number"] = entry.Caller.Line
+	}
+	if entry.Stack != "" {
+		attributes["exception.stacktrace"] = entry.Stack
+	}
+	if len(attributes) == 0 {
+		return nil
+	}
+	return attributes
+}
+
+// otelSeverity maps a level to its OpenTelemetry severity number.
+func otelSeverity(level Level) int {
+	switch {
+	case level >= FatalLvl:
+		return 21
+	case level >= ErrorLvl:
+		return 17
+	case level >= WarnLvl:
+		return 13
+	case level >= InfoLvl:
+		return 9
+	case level >= DebugLvl:
+		return 5
+	}
+	return 1 // trace
+}
@@ -0,0 +1,58 @@
+package alailog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOTelFormatter_Format(t *testing.T) {
+	at := time.Unix(1709647629, 250000000)
+	tests := []struct {
+		name      string
+		formatter OTelFormatter
+		entry     *Entry
+		want      string
+	}{
+		{
+			name:  "message",
+			entry: &Entry{Time: at, Level: WarnLvl, Message: "slow\n"},
+			want:  `{"Timestamp":"1709647629250000000","SeverityNumber":13,"SeverityText":"WARN","Body":"slow"}` + "\n",
+		},
+		{
+			name:      "trace, caller and resource",
+			formatter: OTelFormatter{Resource: map[string]interface{}{"service.name": "api"}},
+			entry: &Entry{
+				Time:    at,
+				Level:   FatalLvl,
+				Message: "panic",
+				Fields:  Fields{TraceIDField: "4bf92f3577b34da6a3ce929d0e0e4736", SpanIDField: "00f067aa0ba902b7", RequestIDField: "r1"},
+				Caller:  &Caller{Function: "main.run", File: "/app/main.go", Line: 12},
+				Stack:   "main.run()",
+			},
+			want: `{"Timestamp":"1709647629250000000","SeverityNumber":21,"SeverityText":"FATAL","Body":"panic",` +
+				`"Attributes":{"code.file.path":"/app/main.go","code.function.name":"main.run","code.line.number":12,` +
+				`"exception.stacktrace":"main.run()","http.request.id":"r1"},"Resource":{"service.name":"api"},` +
+				`"TraceId":"4bf92f3577b34da6a3ce929d0e0e4736","SpanId":"00f067aa0ba902b7"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.formatter.Format(tt.entry)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Format() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOTelSeverity(t *testing.T) {
+	want := map[Level]int{AllLvl: 1, DebugLvl: 5, InfoLvl: 9, WarnLvl: 13, ErrorLvl: 17, FatalLvl: 21}
+	for level, severity := range want {
+		if got := otelSeverity(level); got != severity {
+			t.Errorf("otelSeverity(%s) = %d, want %d", level, got, severity)
+		}
+	}
+}
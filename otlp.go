@@ -0,0 +1,186 @@
+package alailog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultOTLPEndpoint is the logs endpoint of an OpenTelemetry Collector running locally.
+const DefaultOTLPEndpoint = "http://localhost:4318/v1/logs"
+
+// OTLPConfig configures a sink exporting entries with OTLP over HTTP, in the JSON encoding.
+// The batching, retry, queue, header and compression settings are those of HTTPSinkConfig,
+// whose URL is the logs endpoint (DefaultOTLPEndpoint when empty); its NDJSON and Formatter
+// settings are ignored.
+//
+//	ServiceName: the service.name resource attribute ("" uses the program name)
+//	HostName: the host.name resource attribute ("" uses os.Hostname)
+//	Resource: additional resource attributes, such as service.version or deployment.environment
+type OTLPConfig struct {
+	HTTPSinkConfig
+	ServiceName string
+	HostName    string
+	Resource    map[string]interface{}
+}
+
+// NewOTLPSink returns a sink exporting batches of entries as OTLP log records, so that an
+// OpenTelemetry Collector can receive them directly. Records carry the severity, body,
+// attributes and trace context mapped as by OTelFormatter.
+//
+// Example usage:
+//
+//	sink, err := alailog.NewOTLPSink(alailog.OTLPConfig{
+//	    HTTPSinkConfig: alailog.HTTPSinkConfig{URL: "http://collector:4318/v1/logs", Gzip: true},
+//	    ServiceName:    "api",
+//	    Resource:       map[string]interface{}{"service.version": "1.4.2"},
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	logger.AddSink(sink)
+//	defer logger.Close()
+func NewOTLPSink(config OTLPConfig) (*HTTPSink, error) {
+	httpConfig := config.HTTPSinkConfig
+	if httpConfig.URL == "" {
+		httpConfig.URL = DefaultOTLPEndpoint
+	}
+	httpConfig.NDJSON = false
+	httpConfig.Formatter = FormatterFunc(otlpLogRecord)
+
+	attributes := make(map[string]interface{}, len(config.Resource)+2)
+	for key, value := range config.Resource {
+		attributes[key] = value
+	}
+	attributes["service.name"] = config.ServiceName
+	if config.ServiceName == "" {
+		attributes["service.name"] = filepath.Base(os.Args[0])
+	}
+	attributes["host.name"] = config.HostName
+	if config.HostName == "" {
+		attributes["host.name"], _ = os.Hostname()
+	}
+	resource, err := json.Marshal(map[string]interface{}{"attributes": otlpKeyValues(attributes)})
+	if err != nil {
+		return nil, fmt.Errorf("alailog: NewOTLPSink: %w", err)
+	}
+	scope, err := json.Marshal(map[string]string{"name": strings.TrimSuffix(packagePrefix, ".")})
+	if err != nil {
+		return nil, fmt.Errorf("alailog: NewOTLPSink: %w", err)
+	}
+
+	return newHTTPSink(httpConfig, func(records [][]byte) []byte {
+		var body bytes.Buffer
+		body.WriteString(`{"resourceLogs":[{"resource":`)
+		body.Write(resource)
+		body.WriteString(`,"scopeLogs":[{"scope":`)
+		body.Write(scope)
+		body.WriteString(`,"logRecords":[`)
+		body.Write(bytes.Join(records, []byte(",")))
+		body.WriteString(`]}]}]}`)
+		return body.Bytes()
+	})
+}
+
+// otlpRecord is a log record in the OTLP JSON encoding.
+type otlpRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 otlpValue      `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+	TraceID              string         `json:"traceId,omitempty"`
+	SpanID               string         `json:"spanId,omitempty"`
+}
+
+// otlpKeyValue is an attribute in the OTLP JSON encoding.
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+// otlpValue is an AnyValue in the OTLP JSON encoding, where 64-bit integers are strings.
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+// otlpLogRecord renders the entry as an OTLP log record. Trace and span IDs that are not valid
+// hexadecimal IDs are kept as attributes.
+func otlpLogRecord(entry *Entry) ([]byte, error) {
+	attributes := otelAttributes(entry)
+	record := otlpRecord{
+		TimeUnixNano:         strconv.FormatInt(entry.Time.UnixNano(), 10),
+		ObservedTimeUnixNano: strconv.FormatInt(time.Now().UnixNano(), 10),
+		SeverityNumber:       otelSeverity(entry.Level),
+		SeverityText:         entry.Level.String(),
+		Body:                 otlpAnyValue(strings.TrimSuffix(entry.Message, "\n")),
+	}
+	if id, ok := entry.Fields[TraceIDField]; ok {
+		if s, isString := id.(string); isString && isHex(s, 32) {
+			record.TraceID = s
+		} else {
+			attributes = setAttribute(attributes, TraceIDField, id)
+		}
+	}
+	if id, ok := entry.Fields[SpanIDField]; ok {
+		if s, isString := id.(string); isString && isHex(s, 16) {
+			record.SpanID = s
+		} else {
+			attributes = setAttribute(attributes, SpanIDField, id)
+		}
+	}
+	record.Attributes = otlpKeyValues(attributes)
+	return json.Marshal(record)
+}
+
+// setAttribute sets an attribute, allocating the map if needed.
+func setAttribute(attributes map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	if attributes == nil {
+		attributes = make(map[string]interface{})
+	}
+	attributes[key] = value
+	return attributes
+}
+
+// otlpKeyValues returns the attributes sorted by key, in the OTLP JSON encoding.
+func otlpKeyValues(attributes map[string]interface{}) []otlpKeyValue {
+	keyValues := make([]otlpKeyValue, 0, len(attributes))
+	for key, value := range attributes {
+		keyValues = append(keyValues, otlpKeyValue{Key: key, Value: otlpAnyValue(value)})
+	}
+	sort.Slice(keyValues, func(i, j int) bool {
+		return keyValues[i].Key < keyValues[j].Key
+	})
+	return keyValues
+}
+
+// otlpAnyValue converts a value to an OTLP AnyValue. Values other than strings, booleans and
+// numbers are converted to their text.
+func otlpAnyValue(value interface{}) otlpValue {
+	switch v := value.(type) {
+	case string:
+		return otlpValue{StringValue: &v}
+	case bool:
+		return otlpValue{BoolValue: &v}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		s := fmt.Sprintf("%d", v)
+		return otlpValue{IntValue: &s}
+	case float32:
+		f := float64(v)
+		return otlpValue{DoubleValue: &f}
+	case float64:
+		return otlpValue{DoubleValue: &v}
+	}
+	s := fmt.Sprintf("%v", value)
+	return otlpValue{StringValue: &s}
+}
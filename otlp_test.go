@@ -0,0 +1,99 @@
+package alailog
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// otlpRequest is the subset of an OTLP logs request checked by the tests.
+type otlpRequest struct {
+	ResourceLogs []struct {
+		Resource struct {
+			Attributes []otlpKeyValue `json:"attributes"`
+		} `json:"resource"`
+		ScopeLogs []struct {
+			Scope struct {
+				Name string `json:"name"`
+			} `json:"scope"`
+			LogRecords []otlpRecord `json:"logRecords"`
+		} `json:"scopeLogs"`
+	} `json:"resourceLogs"`
+}
+
+func TestNewOTLPSink(t *testing.T) {
+	requests := make(chan otlpRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/logs" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("request to %s with Content-Type %q, want /v1/logs with application/json", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		b, _ := io.ReadAll(r.Body)
+		var request otlpRequest
+		if err := json.Unmarshal(b, &request); err != nil {
+			t.Errorf("request body %s is not OTLP JSON: %v", b, err)
+		}
+		requests <- request
+	}))
+	defer server.Close()
+
+	sink, err := NewOTLPSink(OTLPConfig{
+		HTTPSinkConfig: HTTPSinkConfig{URL: server.URL + "/v1/logs", BatchSize: 2},
+		ServiceName:    "api",
+		HostName:       "web1",
+		Resource:       map[string]interface{}{"service.version": "1.4.2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Unix(1709647629, 250000000)
+	sink.Write(&Entry{Time: at, Level: InfoLvl, Message: "started\n", Fields: Fields{"port": 8080, "tls": true}})
+	sink.Write(&Entry{Time: at, Level: ErrorLvl, Message: "failed", Fields: Fields{
+		TraceIDField: "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanIDField:  "not-a-span-id",
+	}})
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	var request otlpRequest
+	select {
+	case request = <-requests:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no request received")
+	}
+	if len(request.ResourceLogs) != 1 || len(request.ResourceLogs[0].ScopeLogs) != 1 {
+		t.Fatalf("request = %+v, want one resource and one scope", request)
+	}
+	resource := map[string]string{}
+	for _, kv := range request.ResourceLogs[0].Resource.Attributes {
+		resource[kv.Key] = *kv.Value.StringValue
+	}
+	if resource["service.name"] != "api" || resource["host.name"] != "web1" || resource["service.version"] != "1.4.2" {
+		t.Errorf("resource attributes = %v, want service.name, host.name and service.version", resource)
+	}
+	scope := request.ResourceLogs[0].ScopeLogs[0]
+	if scope.Scope.Name != "github.com/josephalai/alailog" {
+		t.Errorf("scope name = %q, want the package path", scope.Scope.Name)
+	}
+	if len(scope.LogRecords) != 2 {
+		t.Fatalf("received %d log records, want 2", len(scope.LogRecords))
+	}
+
+	started, failed := scope.LogRecords[0], scope.LogRecords[1]
+	if started.TimeUnixNano != "1709647629250000000" || started.SeverityNumber != 9 || started.SeverityText != "INFO" || *started.Body.StringValue != "started" {
+		t.Errorf("first record = %+v, want the INFO started record", started)
+	}
+	if len(started.Attributes) != 2 || started.Attributes[0].Key != "port" || *started.Attributes[0].Value.IntValue != "8080" ||
+		started.Attributes[1].Key != "tls" || !*started.Attributes[1].Value.BoolValue {
+		t.Errorf("first record attributes = %+v, want port and tls", started.Attributes)
+	}
+	if failed.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || failed.SpanID != "" {
+		t.Errorf("second record trace context = %q/%q, want the valid trace ID only", failed.TraceID, failed.SpanID)
+	}
+	if len(failed.Attributes) != 1 || failed.Attributes[0].Key != SpanIDField {
+		t.Errorf("second record attributes = %+v, want the invalid span ID kept", failed.Attributes)
+	}
+}
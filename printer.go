@@ -0,0 +1,218 @@
+package alailog
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// SprintMap renders the map as an indented tree with its keys sorted. Nested maps,
+// structs and pointers to structs are expanded below their key, one level of
+// indentation per level of nesting; other values are printed with %v.
+func SprintMap(m map[string]interface{}) string {
+	var b strings.Builder
+	writeTree(&b, reflect.ValueOf(m), 0, DumpConfig{}.withDefaults().MaxDepth)
+	return b.String()
+}
+
+// writeTree writes the entries of a map or the fields of a struct, one per line.
+func writeTree(b *strings.Builder, v reflect.Value, depth, maxDepth int) {
+	indent := strings.Repeat("  ", depth)
+	writeEntry := func(key string, value reflect.Value) {
+		value = treeValue(value)
+		if isTree(value) && depth < maxDepth {
+			fmt.Fprintf(b, "%s%s:\n", indent, key)
+			writeTree(b, value, depth+1, maxDepth)
+			return
+		}
+		fmt.Fprintf(b, "%s%s: %s\n", indent, key, formatCell(value))
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		for _, key := range sortedKeys(v) {
+			writeEntry(fmt.Sprintf("%v", key), v.MapIndex(key))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				writeEntry(v.Type().Field(i).Name, v.Field(i))
+			}
+		}
+	}
+}
+
+// treeValue unwraps interfaces and non-nil pointers so that the underlying map or struct can be expanded.
+func treeValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr) && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+// isTree reports whether the value is a non-empty map or a struct with exported fields, which are expanded as trees.
+func isTree(v reflect.Value) bool {
+	if !v.IsValid() {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		return v.Len() > 0
+	case reflect.Struct:
+		return hasExportedFields(v.Type()) && !v.Type().Implements(stringerType)
+	}
+	return false
+}
+
+// PrintTable logs a slice of structs or maps as an aligned ASCII table at the Info level.
+// Struct rows use their exported fields as columns, in declaration order; map rows use
+// the union of their keys, sorted. Other rows are shown in a single Value column.
+//
+// Example usage:
+//
+//	logger.PrintTable([]User{{Name: "bob", Age: 30}, {Name: "alice", Age: 28}})
+//
+// logs:
+//
+//	+-------+-----+
+//	| Name  | Age |
+//	+-------+-----+
+//	| bob   | 30  |
+//	| alice | 28  |
+//	+-------+-----+
+func (l *Logger) PrintTable(rows interface{}) {
+	l.Log(InfoLvl, SprintTable(rows))
+}
+
+// SprintTable renders rows the way PrintTable logs them. It returns "" when there are no rows.
+func SprintTable(rows interface{}) string {
+	v := treeValue(reflect.ValueOf(rows))
+	if !v.IsValid() {
+		return ""
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		v = reflect.ValueOf([]interface{}{rows})
+	}
+
+	header, cells := tableCells(v)
+	if len(header) == 0 {
+		return ""
+	}
+	widths := make([]int, len(header))
+	for i, name := range header {
+		widths[i] = utf8.RuneCountInString(name)
+	}
+	for _, row := range cells {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	var b strings.Builder
+	writeTableBorder(&b, widths)
+	writeTableRow(&b, widths, header)
+	writeTableBorder(&b, widths)
+	for _, row := range cells {
+		writeTableRow(&b, widths, row)
+	}
+	writeTableBorder(&b, widths)
+	return b.String()
+}
+
+// tableCells returns the column names and the rendered cells of every row.
+func tableCells(rows reflect.Value) ([]string, [][]string) {
+	var header []string
+	seen := make(map[string]bool)
+	addColumn := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			header = append(header, name)
+		}
+	}
+
+	values := make([]reflect.Value, rows.Len())
+	var mapKeys []reflect.Value
+	for i := range values {
+		values[i] = treeValue(rows.Index(i))
+		row := values[i]
+		switch {
+		case row.IsValid() && row.Kind() == reflect.Struct:
+			for j := 0; j < row.NumField(); j++ {
+				if row.Type().Field(j).IsExported() {
+					addColumn(row.Type().Field(j).Name)
+				}
+			}
+		case row.IsValid() && row.Kind() == reflect.Map:
+			mapKeys = append(mapKeys, row.MapKeys()...)
+		default:
+			addColumn("Value")
+		}
+	}
+	sortValues(mapKeys)
+	for _, key := range mapKeys {
+		addColumn(fmt.Sprintf("%v", key))
+	}
+
+	cells := make([][]string, len(values))
+	for i, row := range values {
+		cells[i] = make([]string, len(header))
+		for j, name := range header {
+			cells[i][j] = formatCell(tableCell(row, name))
+		}
+	}
+	return header, cells
+}
+
+// tableCell returns the value of the named column in a row, or the zero Value when the row has no such column.
+func tableCell(row reflect.Value, column string) reflect.Value {
+	if !row.IsValid() {
+		return row
+	}
+	switch row.Kind() {
+	case reflect.Struct:
+		// only the fields of the struct itself are columns, not the promoted ones, which
+		// may be unreachable through a nil embedded pointer
+		if field, ok := row.Type().FieldByName(column); ok && len(field.Index) == 1 && field.IsExported() {
+			return row.Field(field.Index[0])
+		}
+		return reflect.Value{}
+	case reflect.Map:
+		for _, key := range row.MapKeys() {
+			if fmt.Sprintf("%v", key) == column {
+				return row.MapIndex(key)
+			}
+		}
+		return reflect.Value{}
+	}
+	if column == "Value" {
+		return row
+	}
+	return reflect.Value{}
+}
+
+// formatCell renders a value on a single line.
+func formatCell(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+	return strings.ReplaceAll(fmt.Sprintf("%v", v), "\n", `\n`)
+}
+
+// writeTableBorder writes a horizontal border of a table.
+func writeTableBorder(b *strings.Builder, widths []int) {
+	for _, width := range widths {
+		b.WriteString("+" + strings.Repeat("-", width+2))
+	}
+	b.WriteString("+\n")
+}
+
+// writeTableRow writes one row of a table, padding every cell to the width of its column.
+func writeTableRow(b *strings.Builder, widths []int, cells []string) {
+	for i, cell := range cells {
+		b.WriteString("| " + cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)) + " ")
+	}
+	b.WriteString("|\n")
+}
@@ -0,0 +1,146 @@
+package alailog
+
+import (
+	"strings"
+	"testing"
+)
+
+type printerAddress struct {
+	City string
+	Zip  int
+}
+
+type printerRow struct{ X int }
+
+type printerEmbedded struct {
+	*printerRow
+	Y int
+}
+
+type printerUser struct {
+	Name    string
+	Age     int
+	Address *printerAddress
+	secret  string
+}
+
+func TestSprintMap(t *testing.T) {
+	m := map[string]interface{}{
+		"zeta":  1,
+		"alpha": "a",
+		"user":  printerUser{Name: "bob", Age: 30, Address: &printerAddress{City: "Paris", Zip: 75001}, secret: "x"},
+		"nested": map[string]interface{}{
+			"b": true,
+			"a": []int{1, 2},
+		},
+		"empty": map[string]int{},
+	}
+	want := "alpha: a\n" +
+		"empty: map[]\n" +
+		"nested:\n" +
+		"  a: [1 2]\n" +
+		"  b: true\n" +
+		"user:\n" +
+		"  Name: bob\n" +
+		"  Age: 30\n" +
+		"  Address:\n" +
+		"    City: Paris\n" +
+		"    Zip: 75001\n" +
+		"zeta: 1\n"
+	if got := SprintMap(m); got != want {
+		t.Errorf("SprintMap() = %q, want %q", got, want)
+	}
+}
+
+func TestLogger_PrintMap(t *testing.T) {
+	m := map[string]interface{}{"b": 2, "a": 1}
+
+	l, output := newTestLogger(t, InfoLvl)
+	l.Timestamps = true
+	l.TimestampFormat = "ts"
+	l.PrintMap(m)
+	if got, want := output(), "[ts] a: 1\n[ts] b: 2\n"; got != want {
+		t.Errorf("PrintMap() wrote %q, want %q", got, want)
+	}
+
+	l, output = newTestLogger(t, InfoLvl)
+	l.Timestamps = true
+	l.TimestampFormat = "ts"
+	l.SingleEntryMaps = true
+	l.PrintMap(m)
+	if got, want := output(), "[ts] a: 1\nb: 2\n"; got != want {
+		t.Errorf("PrintMap() with SingleEntryMaps wrote %q, want %q", got, want)
+	}
+}
+
+func TestSprintTable(t *testing.T) {
+	tests := []struct {
+		name string
+		rows interface{}
+		want []string
+	}{
+		{
+			name: "structs",
+			rows: []*printerUser{{Name: "bob", Age: 30}, {Name: "alice", Age: 8}},
+			want: []string{
+				"+-------+-----+---------+",
+				"| Name  | Age | Address |",
+				"+-------+-----+---------+",
+				"| bob   | 30  | <nil>   |",
+				"| alice | 8   | <nil>   |",
+				"+-------+-----+---------+",
+			},
+		},
+		{
+			name: "maps",
+			rows: []map[string]interface{}{{"b": 1, "a": "x"}, {"c": 2.5}},
+			want: []string{
+				"+---+---+-----+",
+				"| a | b | c   |",
+				"+---+---+-----+",
+				"| x | 1 |     |",
+				"|   |   | 2.5 |",
+				"+---+---+-----+",
+			},
+		},
+		{
+			name: "scalars",
+			rows: []string{"one", "two"},
+			want: []string{
+				"+-------+",
+				"| Value |",
+				"+-------+",
+				"| one   |",
+				"| two   |",
+				"+-------+",
+			},
+		},
+		{
+			name: "promoted field through a nil pointer",
+			rows: []interface{}{printerRow{X: 1}, printerEmbedded{Y: 2}},
+			want: []string{
+				"+---+---+",
+				"| X | Y |",
+				"+---+---+",
+				"| 1 |   |",
+				"|   | 2 |",
+				"+---+---+",
+			},
+		},
+		{
+			name: "empty",
+			rows: []printerUser{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := ""
+			if len(tt.want) > 0 {
+				want = strings.Join(tt.want, "\n") + "\n"
+			}
+			if got := SprintTable(tt.rows); got != want {
+				t.Errorf("SprintTable() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
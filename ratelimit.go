@@ -0,0 +1,117 @@
+package alailog
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RateLimitConfig configures a token bucket limiting how many entries are logged.
+//
+//	Rate: the number of entries per second the bucket refills with (0 or less means no limit)
+//	Burst: the number of entries that can be logged at once (0 uses Rate, at least 1)
+//	PerCallSite: whether each line of code logging has its own bucket, instead of one for the logger
+type RateLimitConfig struct {
+	Rate        float64
+	Burst       int
+	PerCallSite bool
+}
+
+// rateLimiter holds the token buckets of a logger.
+type rateLimiter struct {
+	config  RateLimitConfig
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	dropped atomic.Uint64
+}
+
+// tokenBucket holds the tokens left for one logger or call site.
+type tokenBucket struct {
+	tokens  float64
+	last    time.Time
+	dropped uint64
+}
+
+// SetRateLimit limits the rate of entries the logger writes, so that a failing dependency
+// cannot fill the outputs. Entries over the limit are dropped, and their number is logged
+// with the next entry allowed through, so it is not reported until the logger (or, with
+// PerCallSite, the same call site) logs again. It replaces the previous limit, if any; a
+// Rate of 0 or less removes it.
+//
+// Example usage:
+//
+//	// at most 5 entries per second from each line of code, in bursts of up to 20
+//	logger.SetRateLimit(alailog.RateLimitConfig{Rate: 5, Burst: 20, PerCallSite: true})
+func (l *Logger) SetRateLimit(config RateLimitConfig) {
+	if config.Rate <= 0 {
+		l.limiter.Store(nil)
+		return
+	}
+	if config.Burst <= 0 {
+		config.Burst = int(math.Max(1, math.Ceil(config.Rate)))
+	}
+	l.limiter.Store(&rateLimiter{config: config, buckets: make(map[string]*tokenBucket)})
+}
+
+// RemoveRateLimit stops limiting the rate of entries.
+func (l *Logger) RemoveRateLimit() {
+	l.limiter.Store(nil)
+}
+
+// RateLimited returns the number of entries dropped by the current rate limit.
+func (l *Logger) RateLimited() uint64 {
+	if r := l.limiter.Load(); r != nil {
+		return r.dropped.Load()
+	}
+	return 0
+}
+
+// rateLimit reports whether the entry is within the rate limit, and returns the entry
+// reporting the entries dropped before it, if any.
+func (l *Logger) rateLimit(r *rateLimiter, entry *Entry) (drops *Entry, keep bool) {
+	site := ""
+	if r.config.PerCallSite {
+		site = callSite()
+	}
+	dropped, ok := r.allow(site, time.Now())
+	if !ok || dropped == 0 {
+		return nil, ok
+	}
+	message := fmt.Sprintf("Rate limit dropped %d entries\n", dropped)
+	if site != "" {
+		message = fmt.Sprintf("Rate limit dropped %d entries from %s\n", dropped, site)
+	}
+	return &Entry{Level: entry.Level, Message: message, color: entry.color}, true
+}
+
+// allow takes a token from the bucket of the call site, returning how many entries it dropped
+// since the last one allowed through, or counts the entry as dropped if the bucket is empty.
+func (r *rateLimiter) allow(site string, now time.Time) (dropped uint64, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, found := r.buckets[site]
+	if !found {
+		b = &tokenBucket{tokens: float64(r.config.Burst), last: now}
+		r.buckets[site] = b
+	}
+	b.tokens = math.Min(float64(r.config.Burst), b.tokens+now.Sub(b.last).Seconds()*r.config.Rate)
+	b.last = now
+	if b.tokens < 1 {
+		b.dropped++
+		r.dropped.Add(1)
+		return 0, false
+	}
+	b.tokens--
+	dropped, b.dropped = b.dropped, 0
+	return dropped, true
+}
+
+// callSite returns the file and line of the first caller outside this package.
+func callSite() string {
+	if caller, ok := externalCaller(); ok {
+		return caller.String()
+	}
+	return ""
+}
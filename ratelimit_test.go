@@ -0,0 +1,90 @@
+package alailog
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogger_SetRateLimit(t *testing.T) {
+	l, output := newTestLogger(t, InfoLvl)
+	l.SetRateLimit(RateLimitConfig{Rate: 0.001, Burst: 2})
+	defer l.RemoveRateLimit()
+
+	for i := 0; i < 5; i++ {
+		l.Infof("retry %d\n", i)
+	}
+	if got, want := output(), "retry 0\nretry 1\n"; got != want {
+		t.Errorf("rate limited output = %q, want %q", got, want)
+	}
+	if got := l.RateLimited(); got != 3 {
+		t.Errorf("RateLimited() = %d, want 3", got)
+	}
+
+	l.RemoveRateLimit()
+	l.Info("done\n")
+	if got := output(); !strings.HasSuffix(got, "done\n") {
+		t.Errorf("output after RemoveRateLimit() = %q, want the entry logged", got)
+	}
+}
+
+func TestLogger_SetRateLimitWithoutRate(t *testing.T) {
+	for _, rate := range []float64{0, -1} {
+		l, output := newTestLogger(t, InfoLvl)
+		l.SetRateLimit(RateLimitConfig{Rate: 0.001, Burst: 1})
+		l.SetRateLimit(RateLimitConfig{Rate: rate})
+		for i := 0; i < 3; i++ {
+			l.Infof("retry %d\n", i)
+		}
+		if got, want := output(), "retry 0\nretry 1\nretry 2\n"; got != want {
+			t.Errorf("output with a rate of %v = %q, want %q", rate, got, want)
+		}
+	}
+}
+
+func TestRateLimiter_allow(t *testing.T) {
+	r := &rateLimiter{config: RateLimitConfig{Rate: 10, Burst: 1}, buckets: make(map[string]*tokenBucket)}
+	now := time.Now()
+	tests := []struct {
+		name        string
+		after       time.Duration
+		wantOK      bool
+		wantDropped uint64
+	}{
+		{name: "burst", after: 0, wantOK: true},
+		{name: "empty", after: 50 * time.Millisecond, wantOK: false},
+		{name: "still empty", after: 90 * time.Millisecond, wantOK: false},
+		{name: "refilled", after: 150 * time.Millisecond, wantOK: true, wantDropped: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dropped, ok := r.allow("", now.Add(tt.after))
+			if ok != tt.wantOK || dropped != tt.wantDropped {
+				t.Errorf("allow() = %d, %v, want %d, %v", dropped, ok, tt.wantDropped, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLogger_RateLimitPerCallSite(t *testing.T) {
+	l, output := newTestLogger(t, InfoLvl)
+	l.SetRateLimit(RateLimitConfig{Rate: 0.001, Burst: 1, PerCallSite: true})
+	defer l.RemoveRateLimit()
+
+	logFirst := func() { l.Info("first site\n") }
+	for i := 0; i < 3; i++ {
+		logFirst()
+		l.Info("second site\n")
+	}
+	if got, want := output(), "first site\nsecond site\n"; got != want {
+		t.Errorf("rate limited output = %q, want one entry per call site %q", got, want)
+	}
+
+	for _, b := range l.limiter.Load().buckets {
+		b.tokens = 1
+	}
+	logFirst()
+	if got := output(); !strings.Contains(got, "Rate limit dropped 2 entries from ") || !strings.Contains(got, "ratelimit_test.go:") {
+		t.Errorf("output = %q, want the dropped entries reported with their call site", got)
+	}
+}
@@ -0,0 +1,84 @@
+package alailog
+
+import "sync"
+
+// flightRecorder keeps the last entries of a logger, whether they were written or not.
+type flightRecorder struct {
+	mu      sync.Mutex
+	entries []recordedEntry
+	next    int
+	full    bool
+}
+
+// recordedEntry is an entry kept by the flight recorder.
+type recordedEntry struct {
+	entry   Entry
+	written bool
+}
+
+// EnableFlightRecorder keeps the last size entries of every level in memory, including the
+// entries below the level of the logger. When an Error or Fatal entry is logged, the kept
+// entries that were not written are written before it, giving the detail leading to the
+// failure without writing Debug entries all the time. It replaces the previous recorder, if any.
+//
+// Example usage:
+//
+//	logger.SetLevel(alailog.InfoLvl)
+//	logger.EnableFlightRecorder(100)
+//	logger.Log(alailog.DebugLvl, "connecting to "+addr+"\n") // kept in memory
+//	logger.Error("connection refused\n")                     // writes the debug entry, then the error
+func (l *Logger) EnableFlightRecorder(size int) {
+	if size <= 0 {
+		l.recorder.Store(nil)
+		return
+	}
+	l.recorder.Store(&flightRecorder{entries: make([]recordedEntry, size)})
+}
+
+// DisableFlightRecorder stops keeping entries in memory and discards the kept ones.
+func (l *Logger) DisableFlightRecorder() {
+	l.recorder.Store(nil)
+}
+
+// record keeps an entry that is not written.
+func (r *flightRecorder) record(entry *Entry) {
+	r.add(recordedEntry{entry: *entry})
+}
+
+// recordWritten keeps an entry that is about to be written, and returns the kept entries to
+// write before it when it is an Error or Fatal entry.
+func (r *flightRecorder) recordWritten(entry *Entry) []Entry {
+	if entry.Level < ErrorLvl {
+		r.add(recordedEntry{entry: *entry, written: true})
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unwritten []Entry
+	for _, e := range r.ordered() {
+		if !e.written {
+			unwritten = append(unwritten, e.entry)
+		}
+	}
+	r.next, r.full = 0, false
+	return unwritten
+}
+
+// add appends an entry to the ring, overwriting the oldest one when it is full.
+func (r *flightRecorder) add(e recordedEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = e
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// ordered returns the kept entries, oldest first. It must be called with r.mu held.
+func (r *flightRecorder) ordered() []recordedEntry {
+	if !r.full {
+		return r.entries[:r.next]
+	}
+	return append(append([]recordedEntry(nil), r.entries[r.next:]...), r.entries[:r.next]...)
+}
@@ -0,0 +1,86 @@
+package alailog
+
+import "testing"
+
+func TestLogger_EnableFlightRecorder(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+		log  func(l *Logger)
+		want string
+	}{
+		{
+			name: "kept entries written before an error",
+			size: 10,
+			log: func(l *Logger) {
+				l.Log(DebugLvl, "connecting\n")
+				l.Info("started\n")
+				l.Log(DebugLvl, "retrying\n")
+				l.Error("refused\n")
+			},
+			want: "started\nconnecting\nretrying\nrefused\n",
+		},
+		{
+			name: "only the last entries kept",
+			size: 2,
+			log: func(l *Logger) {
+				l.Log(DebugLvl, "one\n")
+				l.Log(DebugLvl, "two\n")
+				l.Log(DebugLvl, "three\n")
+				l.Fatal("failed\n")
+			},
+			want: "two\nthree\nfailed\n",
+		},
+		{
+			name: "written entries count towards the size",
+			size: 2,
+			log: func(l *Logger) {
+				l.Log(DebugLvl, "one\n")
+				l.Info("two\n")
+				l.Warn("three\n")
+				l.Error("failed\n")
+			},
+			want: "two\nthree\nfailed\n",
+		},
+		{
+			name: "kept entries written once",
+			size: 10,
+			log: func(l *Logger) {
+				l.Log(DebugLvl, "one\n")
+				l.Error("first\n")
+				l.Error("second\n")
+			},
+			want: "one\nfirst\nsecond\n",
+		},
+		{
+			name: "nothing written without an error",
+			size: 10,
+			log: func(l *Logger) {
+				l.Log(DebugLvl, "one\n")
+				l.Info("two\n")
+			},
+			want: "two\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, output := newTestLogger(t, InfoLvl)
+			l.EnableFlightRecorder(tt.size)
+			tt.log(l)
+			if got := output(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogger_DisableFlightRecorder(t *testing.T) {
+	l, output := newTestLogger(t, InfoLvl)
+	l.EnableFlightRecorder(10)
+	l.Log(DebugLvl, "kept\n")
+	l.DisableFlightRecorder()
+	l.Error("failed\n")
+	if got, want := output(), "failed\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,75 @@
+package alailog
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+// Recover recovers from a panic in the calling goroutine and logs the panic value with
+// the stack trace at the Error level. It must be called directly with defer:
+//
+//	func worker() {
+//	    defer logger.Recover()
+//	    ...
+//	}
+func (l *Logger) Recover() {
+	if value := recover(); value != nil {
+		l.logPanic(ErrorLvl, value, nil)
+	}
+}
+
+// RecoverAndPanic logs a panic like Recover, at the Fatal level, then panics again with
+// the same value so the program still crashes. It must be called directly with defer.
+func (l *Logger) RecoverAndPanic() {
+	if value := recover(); value != nil {
+		l.logPanic(FatalLvl, value, nil)
+		panic(value)
+	}
+}
+
+// Go runs fn in a new goroutine, logging any panic it raises instead of crashing the program.
+//
+// Example usage:
+//
+//	logger.Go(func() {
+//	    processQueue(queue)
+//	})
+func (l *Logger) Go(fn func()) {
+	go func() {
+		defer l.Recover()
+		fn()
+	}()
+}
+
+// RecoverMiddleware returns an http.Handler that logs panics raised by next, with the fields
+// carried by the request context, and responds with 500 Internal Server Error.
+// http.ErrAbortHandler panics are passed through, as the http package expects.
+func (l *Logger) RecoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			value := recover()
+			if value == nil {
+				return
+			}
+			if value == http.ErrAbortHandler {
+				panic(value)
+			}
+			fields := l.contextFields(r.Context()).merge(Fields{"method": r.Method, "path": r.URL.Path})
+			l.logPanic(ErrorLvl, value, fields)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// logPanic logs a recovered panic value with the stack trace of the panicking goroutine.
+func (l *Logger) logPanic(level Level, value interface{}, fields Fields) {
+	l.logEntry(&Entry{
+		Level:   level,
+		Message: fmt.Sprintf("panic: %v\n", value),
+		Fields:  fields,
+		Stack:   string(debug.Stack()),
+		color:   l.textColor,
+	})
+}
@@ -0,0 +1,72 @@
+package alailog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogger_Recover(t *testing.T) {
+	l, output := newTestLogger(t, ErrorLvl)
+	func() {
+		defer l.Recover()
+		panic("boom")
+	}()
+
+	got := output()
+	if !strings.HasPrefix(got, "panic: boom\ngoroutine ") || !strings.Contains(got, "recover_test.go") {
+		t.Errorf("Recover() logged %q, want the panic value and stack trace", got)
+	}
+}
+
+func TestLogger_RecoverAndPanic(t *testing.T) {
+	l, output := newTestLogger(t, FatalLvl)
+	defer func() {
+		if value := recover(); value != "boom" {
+			t.Errorf("recovered %v, want the original panic value", value)
+		}
+		if !strings.HasPrefix(output(), "panic: boom\n") {
+			t.Errorf("RecoverAndPanic() logged %q, want the panic", output())
+		}
+	}()
+	defer l.RecoverAndPanic()
+	panic("boom")
+}
+
+func TestLogger_Go(t *testing.T) {
+	l, output := newTestLogger(t, ErrorLvl)
+	l.Go(func() {
+		panic("in goroutine")
+	})
+
+	// the panic is logged by the goroutine after fn returns
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(output(), "panic: in goroutine") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if !strings.Contains(output(), "panic: in goroutine") {
+		t.Errorf("Go() logged %q, want the panic", output())
+	}
+}
+
+func TestLogger_RecoverMiddleware(t *testing.T) {
+	l, output := newTestLogger(t, ErrorLvl)
+	handler := l.RecoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("handler failed")
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	req = req.WithContext(ContextWithRequestID(context.Background(), "req-1"))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rec.Code)
+	}
+	if got := output(); !strings.HasPrefix(got, "panic: handler failed method=POST path=/orders request_id=req-1\n") {
+		t.Errorf("RecoverMiddleware() logged %q, want the panic with request fields", got)
+	}
+}
@@ -0,0 +1,216 @@
+package alailog
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SamplerConfig configures the sampling of repeated entries. Entries are grouped by level
+// and message template: the format of Infof-style calls, or the message itself otherwise.
+//
+//	Interval: the length of a sampling period (0 uses one second)
+//	First: how many entries of a group are logged in each period before sampling starts
+//	Thereafter: after the first ones, every Thereafter-th entry is logged (0 drops them all)
+//	ReportInterval: how often the number of sampled-out entries is logged (0 never logs it)
+type SamplerConfig struct {
+	Interval       time.Duration
+	First          int
+	Thereafter     int
+	ReportInterval time.Duration
+}
+
+// maxSampleGroups is the number of groups a sampler tracks before it forgets the idle ones.
+const maxSampleGroups = 10000
+
+// sampler decides which entries of each group are kept, and counts the others.
+type sampler struct {
+	config   SamplerConfig
+	mu       sync.Mutex
+	groups   map[sampleKey]*sampleGroup
+	dropped  atomic.Uint64
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// sampleKey identifies a group of similar entries.
+type sampleKey struct {
+	level    Level
+	template string
+}
+
+// sampleGroup counts the entries of a group in the current period.
+type sampleGroup struct {
+	start   time.Time
+	seen    int
+	dropped uint64
+}
+
+// SetSampler starts sampling the entries of the logger, so that hot loops logging the same
+// message keep representative entries without flooding the outputs. It replaces the previous
+// sampler, if any.
+//
+// Example usage:
+//
+//	// per second, log the first 10 identical entries, then one in 100
+//	logger.SetSampler(alailog.SamplerConfig{First: 10, Thereafter: 100, ReportInterval: time.Minute})
+func (l *Logger) SetSampler(config SamplerConfig) {
+	if config.Interval <= 0 {
+		config.Interval = time.Second
+	}
+	s := &sampler{
+		config: config,
+		groups: make(map[sampleKey]*sampleGroup),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	if config.ReportInterval > 0 {
+		go l.reportSampled(s)
+	} else {
+		close(s.done)
+	}
+	if old := l.sampler.Swap(s); old != nil {
+		old.close()
+	}
+}
+
+// RemoveSampler stops sampling: every entry is logged again.
+func (l *Logger) RemoveSampler() {
+	if old := l.sampler.Swap(nil); old != nil {
+		old.close()
+	}
+}
+
+// SampledOut returns the number of entries dropped by the current sampler.
+func (l *Logger) SampledOut() uint64 {
+	if s := l.sampler.Load(); s != nil {
+		return s.dropped.Load()
+	}
+	return 0
+}
+
+// LogSampledOut logs, at the Info level, how many entries of each group were dropped since the
+// last report, and resets those counts. It is called every ReportInterval when one is configured.
+func (l *Logger) LogSampledOut() {
+	if s := l.sampler.Load(); s != nil {
+		l.logSampled(s)
+	}
+}
+
+// reportSampled logs the sampled-out counts every ReportInterval until the sampler is closed.
+func (l *Logger) reportSampled(s *sampler) {
+	defer close(s.done)
+	ticker := time.NewTicker(s.config.ReportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			l.logSampled(s)
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// logSampled logs and resets the dropped counts of a sampler. The report itself is never sampled.
+func (l *Logger) logSampled(s *sampler) {
+	for _, report := range s.takeDropped() {
+		if InfoLvl < l.level {
+			return
+		}
+		l.emit(&Entry{
+			Level:   InfoLvl,
+			Message: fmt.Sprintf("Sampler dropped %d %s entries: %q\n", report.dropped, report.key.level, report.key.template),
+			color:   l.textColor,
+		})
+	}
+}
+
+// sample reports whether the entry is kept, counting it as dropped otherwise.
+func (s *sampler) sample(entry *Entry) bool {
+	key := sampleKey{level: entry.Level, template: entry.template}
+	if key.template == "" {
+		key.template = entry.Message
+	}
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.groups[key]
+	if !ok {
+		if len(s.groups) >= maxSampleGroups {
+			s.forgetIdle(now)
+		}
+		g = &sampleGroup{start: now}
+		s.groups[key] = g
+	}
+	if now.Sub(g.start) >= s.config.Interval {
+		g.start = now
+		g.seen = 0
+	}
+	g.seen++
+	if g.seen <= s.config.First {
+		return true
+	}
+	if s.config.Thereafter > 0 && (g.seen-s.config.First)%s.config.Thereafter == 0 {
+		return true
+	}
+	g.dropped++
+	s.dropped.Add(1)
+	return false
+}
+
+// forgetIdle removes the groups whose period has ended, so that messages that are never
+// repeated do not grow the sampler without bound. Their dropped counts stay in the total.
+func (s *sampler) forgetIdle(now time.Time) {
+	for key, g := range s.groups {
+		if now.Sub(g.start) >= s.config.Interval {
+			delete(s.groups, key)
+		}
+	}
+}
+
+// sampledReport is the number of entries dropped for one group.
+type sampledReport struct {
+	key     sampleKey
+	dropped uint64
+}
+
+// takeDropped returns the groups with dropped entries, sorted by level and template,
+// resets their counts and forgets the groups that are idle.
+func (s *sampler) takeDropped() []sampledReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var reports []sampledReport
+	now := time.Now()
+	for key, g := range s.groups {
+		if g.dropped > 0 {
+			reports = append(reports, sampledReport{key: key, dropped: g.dropped})
+			g.dropped = 0
+		} else if now.Sub(g.start) >= s.config.Interval {
+			delete(s.groups, key)
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].key.level != reports[j].key.level {
+			return reports[i].key.level < reports[j].key.level
+		}
+		return reports[i].key.template < reports[j].key.template
+	})
+	return reports
+}
+
+// close stops the report goroutine of the sampler.
+func (s *sampler) close() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+}
+
+// wait waits until the report goroutine of the sampler, if any, has returned.
+func (s *sampler) wait() {
+	<-s.done
+}
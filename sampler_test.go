@@ -0,0 +1,74 @@
+package alailog
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogger_SetSampler(t *testing.T) {
+	l, output := newTestLogger(t, InfoLvl)
+	l.SetSampler(SamplerConfig{Interval: time.Hour, First: 2, Thereafter: 3})
+	defer l.RemoveSampler()
+
+	for i := 0; i < 10; i++ {
+		l.Infof("item %d\n", i)
+	}
+	l.Warningf("item %d\n", 99)
+
+	// items 0 and 1 are the first ones, then every third: 4 and 7
+	want := "item 0\nitem 1\nitem 4\nitem 7\nitem 99\n"
+	if got := output(); got != want {
+		t.Errorf("sampled output = %q, want %q", got, want)
+	}
+	if got := l.SampledOut(); got != 6 {
+		t.Errorf("SampledOut() = %d, want 6", got)
+	}
+
+	l.LogSampledOut()
+	if got := output(); !strings.HasSuffix(got, "Sampler dropped 6 INFO entries: \"item %d\\n\"\n") {
+		t.Errorf("LogSampledOut() wrote %q, want the dropped count", got)
+	}
+	l.LogSampledOut()
+	if got := strings.Count(output(), "Sampler dropped"); got != 1 {
+		t.Errorf("LogSampledOut() reported %d times, want the counts reset after the first report", got)
+	}
+}
+
+func TestLogger_SamplerInterval(t *testing.T) {
+	l, output := newTestLogger(t, InfoLvl)
+	l.SetSampler(SamplerConfig{Interval: 20 * time.Millisecond, First: 1})
+	defer l.RemoveSampler()
+
+	l.Info("tick\n")
+	l.Info("tick\n")
+	time.Sleep(30 * time.Millisecond)
+	l.Info("tick\n")
+	if got := output(); got != "tick\ntick\n" {
+		t.Errorf("sampled output = %q, want one entry per interval", got)
+	}
+
+	l.RemoveSampler()
+	l.Info("tick\n")
+	l.Info("tick\n")
+	if got := strings.Count(output(), "tick"); got != 4 {
+		t.Errorf("logged %d entries, want every entry after RemoveSampler()", got)
+	}
+}
+
+func TestLogger_CloseStopsSampler(t *testing.T) {
+	l, _ := newTestLogger(t, InfoLvl)
+	l.SetSampler(SamplerConfig{Interval: time.Hour, First: 1, ReportInterval: 5 * time.Millisecond})
+	s := l.sampler.Load()
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	select {
+	case <-s.done:
+	case <-time.After(time.Second):
+		t.Fatal("Close() did not stop the report goroutine")
+	}
+	if l.sampler.Load() != nil {
+		t.Error("Close() did not remove the sampler")
+	}
+}
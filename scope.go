@@ -0,0 +1,193 @@
+package alailog
+
+import (
+	"fmt"
+	"sync"
+)
+
+// DefaultScopeMaxEntries and DefaultScopeMaxBytes cap the entries buffered by a scope
+// whose ScopeConfig leaves the limits at 0.
+const (
+	DefaultScopeMaxEntries = 10000
+	DefaultScopeMaxBytes   = 1 << 20
+)
+
+// ScopeConfig caps the entries buffered by a scope. When a cap is reached, the oldest
+// entries are dropped, and their number is logged before the others.
+//
+//	MaxEntries: the number of entries buffered (0 uses DefaultScopeMaxEntries)
+//	MaxBytes: the total length of the buffered messages (0 uses DefaultScopeMaxBytes)
+type ScopeConfig struct {
+	MaxEntries int
+	MaxBytes   int
+}
+
+// Scope buffers the entries of one unit of work, such as a batch job or a request, and writes
+// them only if it fails. Entries of every level are buffered, including the ones below the level
+// of the logger. A Scope is safe for concurrent use.
+type Scope struct {
+	logger  *Logger
+	config  ScopeConfig
+	mu      sync.Mutex
+	entries []Entry
+	bytes   int
+	dropped int
+	failed  bool
+	ended   bool
+}
+
+// BeginScope starts buffering the entries of a unit of work. End the scope with Success, which
+// discards the entries unless an Error or Fatal entry was logged, or with Fail, which writes them.
+// Entries logged to the scope after it ended are logged directly.
+//
+// Example usage:
+//
+//	s := logger.BeginScope()
+//	s.Debugf("processing %d rows\n", len(rows))
+//	if err := process(rows); err != nil {
+//	    s.Errorf("job failed: %v\n", err)
+//	}
+//	s.Success() // writes the debug entry only if the job failed
+func (l *Logger) BeginScope(config ...ScopeConfig) *Scope {
+	s := &Scope{logger: l}
+	if len(config) > 0 {
+		s.config = config[0]
+	}
+	if s.config.MaxEntries <= 0 {
+		s.config.MaxEntries = DefaultScopeMaxEntries
+	}
+	if s.config.MaxBytes <= 0 {
+		s.config.MaxBytes = DefaultScopeMaxBytes
+	}
+	return s
+}
+
+// Log buffers a message at the specified level.
+func (s *Scope) Log(level Level, message interface{}) {
+	s.add(&Entry{Level: level, Message: fmt.Sprintf("%s", message), color: s.logger.textColor})
+}
+
+// Debug buffers a debug message.
+func (s *Scope) Debug(message interface{}) {
+	s.Log(DebugLvl, message)
+}
+
+// Info buffers an info message.
+func (s *Scope) Info(message interface{}) {
+	s.Log(InfoLvl, message)
+}
+
+// Warn buffers a warning message.
+func (s *Scope) Warn(message interface{}) {
+	s.Log(WarnLvl, message)
+}
+
+// Error buffers an error message and marks the scope as failed.
+func (s *Scope) Error(message interface{}) {
+	s.Log(ErrorLvl, message)
+}
+
+// Fatal buffers a fatal message and marks the scope as failed.
+func (s *Scope) Fatal(message interface{}) {
+	s.Log(FatalLvl, message)
+}
+
+// Debugf formats and buffers a debug message.
+func (s *Scope) Debugf(format string, args ...interface{}) {
+	s.logf(DebugLvl, format, args)
+}
+
+// Infof formats and buffers an info message.
+func (s *Scope) Infof(format string, args ...interface{}) {
+	s.logf(InfoLvl, format, args)
+}
+
+// Warningf formats and buffers a warning message.
+func (s *Scope) Warningf(format string, args ...interface{}) {
+	s.logf(WarnLvl, format, args)
+}
+
+// Errorf formats and buffers an error message and marks the scope as failed.
+func (s *Scope) Errorf(format string, args ...interface{}) {
+	s.logf(ErrorLvl, format, args)
+}
+
+// Fatalf formats and buffers a fatal message and marks the scope as failed.
+func (s *Scope) Fatalf(format string, args ...interface{}) {
+	s.logf(FatalLvl, format, args)
+}
+
+// Failed reports whether an Error or Fatal entry was logged to the scope, or Fail was called.
+func (s *Scope) Failed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.failed
+}
+
+// Success ends the scope, discarding the buffered entries unless the scope failed.
+func (s *Scope) Success() {
+	s.end(false)
+}
+
+// Fail ends the scope and writes the buffered entries.
+func (s *Scope) Fail() {
+	s.end(true)
+}
+
+// logf formats the message and buffers it, keeping the format as the template of the entry.
+func (s *Scope) logf(level Level, format string, args []interface{}) {
+	s.add(&Entry{Level: level, Message: fmt.Sprintf(format, args...), template: format, color: s.logger.textColor})
+}
+
+// add buffers the entry, dropping the oldest ones over the caps, or logs it if the scope ended.
+func (s *Scope) add(entry *Entry) {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		s.logger.logEntry(entry)
+		return
+	}
+	defer s.mu.Unlock()
+	s.logger.stamp(entry)
+	if entry.Level >= ErrorLvl {
+		s.failed = true
+	}
+	s.entries = append(s.entries, *entry)
+	s.bytes += len(entry.Message)
+	for len(s.entries) > s.config.MaxEntries || (s.bytes > s.config.MaxBytes && len(s.entries) > 1) {
+		s.bytes -= len(s.entries[0].Message)
+		s.entries[0] = Entry{}
+		s.entries = s.entries[1:]
+		s.dropped++
+	}
+}
+
+// end ends the scope, writing the buffered entries if it failed.
+func (s *Scope) end(fail bool) {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	entries, dropped := s.entries, s.dropped
+	write := fail || s.failed
+	s.failed = write
+	s.entries = nil
+	s.mu.Unlock()
+
+	if !write {
+		return
+	}
+	if dropped > 0 {
+		s.logger.emit(&Entry{
+			Level:   WarnLvl,
+			Message: fmt.Sprintf("Scope dropped %d earlier entries\n", dropped),
+			Time:    entries[0].Time,
+			color:   s.logger.textColor,
+		})
+	}
+	for i := range entries {
+		s.logger.emit(&entries[i])
+	}
+}
@@ -0,0 +1,95 @@
+package alailog
+
+import "testing"
+
+func TestLogger_BeginScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		config ScopeConfig
+		log    func(s *Scope)
+		want   string
+	}{
+		{
+			name: "success discards",
+			log: func(s *Scope) {
+				s.Debug("step\n")
+				s.Info("done\n")
+				s.Success()
+			},
+			want: "",
+		},
+		{
+			name: "fail writes every level",
+			log: func(s *Scope) {
+				s.Debugf("step %d\n", 1)
+				s.Warn("slow\n")
+				s.Fail()
+			},
+			want: "step 1\nslow\n",
+		},
+		{
+			name: "error writes on success",
+			log: func(s *Scope) {
+				s.Debug("step\n")
+				s.Errorf("failed: %s\n", "timeout")
+				s.Success()
+			},
+			want: "step\nfailed: timeout\n",
+		},
+		{
+			name:   "entries over the cap dropped",
+			config: ScopeConfig{MaxEntries: 2},
+			log: func(s *Scope) {
+				s.Debug("one\n")
+				s.Debug("two\n")
+				s.Debug("three\n")
+				s.Fail()
+			},
+			want: "Scope dropped 1 earlier entries\ntwo\nthree\n",
+		},
+		{
+			name:   "bytes over the cap dropped",
+			config: ScopeConfig{MaxBytes: 8},
+			log: func(s *Scope) {
+				s.Debug("one\n")
+				s.Debug("two\n")
+				s.Debug("three\n")
+				s.Fail()
+			},
+			want: "Scope dropped 2 earlier entries\nthree\n",
+		},
+		{
+			name: "logged directly after the end",
+			log: func(s *Scope) {
+				s.Info("buffered\n")
+				s.Success()
+				s.Debug("below level\n")
+				s.Info("after\n")
+				s.Fail()
+			},
+			want: "after\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, output := newTestLogger(t, InfoLvl)
+			tt.log(l.BeginScope(tt.config))
+			if got := output(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScope_Failed(t *testing.T) {
+	l, _ := newTestLogger(t, InfoLvl)
+	s := l.BeginScope()
+	s.Warn("slow\n")
+	if s.Failed() {
+		t.Error("Failed() = true after a warning, want false")
+	}
+	s.Fatal("crashed\n")
+	if !s.Failed() {
+		t.Error("Failed() = false after a fatal entry, want true")
+	}
+}
@@ -0,0 +1,132 @@
+package alailog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Sink receives the entries written by a Logger, in addition to the file, stdout, stderr and
+// writers, and sends them to a destination such as syslog or a remote collector. Unlike a writer,
+// a sink receives the entry itself and renders it in the format of its destination.
+//
+//	Write: sends one entry; a returned error is reported to the logger's error handler
+//	Close: flushes the pending entries and releases the connection of the sink
+type Sink interface {
+	Write(entry *Entry) error
+	Close() error
+}
+
+// sinkSet holds the sinks of a logger.
+type sinkSet struct {
+	mu   sync.RWMutex
+	list []Sink
+}
+
+// AddSink registers a sink receiving the entries written by the logger. Sinks are closed by Close.
+//
+// Example usage:
+//
+//	sink, err := alailog.NewSyslogSink(alailog.SyslogConfig{AppName: "api"})
+//	if err != nil {
+//	    return err
+//	}
+//	logger.AddSink(sink)
+//	defer logger.Close()
+func (l *Logger) AddSink(sink Sink) {
+	l.sinks.mu.Lock()
+	defer l.sinks.mu.Unlock()
+	l.sinks.list = append(l.sinks.list, sink)
+}
+
+// Close logs the pending deduplication count, removes the sampler, waits for the asynchronous
+// hooks and stops them, then closes and removes the sinks of the logger and closes the log,
+// level and rotated files it opened; a file passed to NewLogger is left open. It returns the
+// errors of the sinks and files that failed to close.
+func (l *Logger) Close() error {
+	l.DisableDeduplication()
+	if s := l.sampler.Swap(nil); s != nil {
+		s.close()
+		s.wait()
+	}
+	l.hooks.close()
+	l.sinks.mu.Lock()
+	sinks := l.sinks.list
+	l.sinks.list = nil
+	l.sinks.mu.Unlock()
+
+	var errs []error
+	for _, sink := range sinks {
+		if err := sink.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("alailog: close %s: %w", sinkName(sink), err))
+		}
+	}
+	files := make([]*rotatingFile, 0, len(l.levelFiles)+1)
+	for _, w := range l.writers {
+		if file, ok := w.(*rotatingFile); ok {
+			files = append(files, file)
+		}
+	}
+	for _, level := range AllLevels() {
+		if file := l.levelFiles[level]; file != nil {
+			files = append(files, file)
+		}
+	}
+	for _, file := range files {
+		if err := file.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("alailog: close %s: %w", file.Name(), err))
+		}
+	}
+	if l.ownsFile && l.file != nil {
+		if err := l.file.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+			errs = append(errs, fmt.Errorf("alailog: close %s: %w", l.file.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// writeSinks sends the entry to the sinks, reporting the failed writes to the error handler.
+func (l *Logger) writeSinks(entry *Entry) {
+	l.sinks.mu.RLock()
+	defer l.sinks.mu.RUnlock()
+	for _, sink := range l.sinks.list {
+		if err := sink.Write(entry); err != nil {
+			l.reportWriteError(sinkName(sink), err)
+		}
+	}
+}
+
+// sinkName describes a sink in errors: its String method if it has one, or its type.
+func sinkName(sink Sink) string {
+	if s, ok := sink.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", sink)
+}
+
+// asyncError keeps the last error of the background goroutine of a sink,
+// until it is returned by the next call to Write or Close.
+type asyncError struct {
+	mu  sync.Mutex
+	err error
+}
+
+// set keeps the error, unless it is nil.
+func (e *asyncError) set(err error) {
+	if err == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.err = err
+}
+
+// take returns and clears the kept error.
+func (e *asyncError) take() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	err := e.err
+	e.err = nil
+	return err
+}
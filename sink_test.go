@@ -0,0 +1,122 @@
+package alailog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// recordingSink keeps the entries it receives.
+type recordingSink struct {
+	mu       sync.Mutex
+	messages []string
+	err      error
+	closed   bool
+}
+
+func (s *recordingSink) Write(entry *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, entry.Message)
+	return s.err
+}
+
+func (s *recordingSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return s.err
+}
+
+func TestLogger_AddSink(t *testing.T) {
+	l, _ := newTestLogger(t, InfoLvl)
+	sink := &recordingSink{}
+	l.AddSink(sink)
+
+	l.Log(DebugLvl, "below level\n")
+	l.Info("one\n")
+	l.Error("two\n")
+	if got := len(sink.messages); got != 2 {
+		t.Errorf("sink received %d entries, want 2", got)
+	}
+
+	if err := l.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if !sink.closed {
+		t.Error("Close() did not close the sink")
+	}
+	l.Info("three\n")
+	if got := len(sink.messages); got != 2 {
+		t.Errorf("sink received %d entries after Close(), want 2", got)
+	}
+}
+
+func TestLogger_SinkErrors(t *testing.T) {
+	l, _ := newTestLogger(t, InfoLvl)
+	var reported []error
+	l.SetErrorHandler(func(err error) { reported = append(reported, err) })
+	sinkErr := errors.New("unreachable")
+	l.AddSink(&recordingSink{err: sinkErr})
+
+	l.Info("one\n")
+	var writeErr *WriteError
+	if len(reported) != 1 || !errors.As(reported[0], &writeErr) || !errors.Is(writeErr, sinkErr) {
+		t.Fatalf("reported errors = %v, want the sink write error", reported)
+	}
+	if l.FailedWrites() != 1 {
+		t.Errorf("FailedWrites() = %d, want 1", l.FailedWrites())
+	}
+	if err := l.Close(); !errors.Is(err, sinkErr) {
+		t.Errorf("Close() error = %v, want %v", err, sinkErr)
+	}
+}
+
+func TestLogger_CloseFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		open       func(filename string) (*Logger, error)
+		wantClosed bool
+	}{
+		{
+			name:       "New with WithFile",
+			open:       func(filename string) (*Logger, error) { return New(WithFile(filename), WithStdout(false)) },
+			wantClosed: true,
+		},
+		{
+			name: "NewLoggerFromParameter",
+			open: func(filename string) (*Logger, error) {
+				return NewLoggerFromParameter(&Parameter{Filename: filename, Level: InfoLvl})
+			},
+			wantClosed: true,
+		},
+		{
+			name: "NewLogger",
+			open: func(filename string) (*Logger, error) {
+				file, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+				if err != nil {
+					return nil, err
+				}
+				t.Cleanup(func() { file.Close() })
+				return NewLogger(file, InfoLvl, false, false, false, BgBlack, White, false, ""), nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := tt.open(filepath.Join(t.TempDir(), "logs.txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := l.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			_, err = l.file.Write([]byte("after close\n"))
+			if closed := errors.Is(err, os.ErrClosed); closed != tt.wantClosed {
+				t.Errorf("file closed = %v (write error %v), want %v", closed, err, tt.wantClosed)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package alailog
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Facility is the syslog facility of the messages sent by a SyslogSink.
+type Facility int
+
+// The syslog facilities, as defined by RFC 5424.
+const (
+	FacilityKern Facility = iota
+	FacilityUser
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLPR
+	FacilityNews
+	FacilityUUCP
+	FacilityCron
+	FacilityAuthPriv
+	FacilityFTP
+	FacilityLocal0 Facility = iota + 4
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+// syslogSockets are the paths of the local syslog socket, tried in order.
+var syslogSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogConfig configures a SyslogSink.
+//
+//	Network: "udp", "tcp", "unix" or "unixgram" ("" uses the local syslog socket, such as /dev/log)
+//	Address: the address or socket path of the syslog server (ignored when Network is "")
+//	Facility: the facility of the messages (0 is FacilityKern; most programs use FacilityUser)
+//	Hostname: the host reported in the messages ("" uses os.Hostname)
+//	AppName: the application reported in the messages ("" uses the program name)
+//	RFC3164: whether to send the older BSD format instead of RFC 5424 (always set for the local
+//	syslog socket, as local daemons expect it)
+type SyslogConfig struct {
+	Network  string
+	Address  string
+	Facility Facility
+	Hostname string
+	AppName  string
+	RFC3164  bool
+}
+
+// SyslogSink sends entries to a syslog server, reconnecting when the connection fails.
+// The level of an entry is mapped to the syslog severity, and its fields are appended to the message.
+type SyslogSink struct {
+	config SyslogConfig
+	pid    int
+	mu     sync.Mutex
+	conn   net.Conn
+	// The network of conn, which frames the messages
+	network string
+}
+
+// NewSyslogSink connects to the syslog server and returns a sink sending entries to it.
+//
+// Example usage:
+//
+//	sink, err := alailog.NewSyslogSink(alailog.SyslogConfig{
+//	    Network:  "udp",
+//	    Address:  "logs.example.com:514",
+//	    Facility: alailog.FacilityLocal0,
+//	    AppName:  "api",
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	logger.AddSink(sink)
+func NewSyslogSink(config SyslogConfig) (*SyslogSink, error) {
+	if config.Hostname == "" {
+		config.Hostname, _ = os.Hostname()
+	}
+	if config.AppName == "" {
+		config.AppName = filepath.Base(os.Args[0])
+	}
+	if config.Network == "" {
+		config.RFC3164 = true
+	}
+	s := &SyslogSink{config: config, pid: os.Getpid()}
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Write sends the entry to the syslog server. If the connection fails, the sink reconnects
+// and sends the entry again once.
+func (s *SyslogSink) Write(entry *Entry) error {
+	message := s.format(entry)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		if _, err := s.conn.Write(s.frame(message)); err == nil {
+			return nil
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+	if err := s.connect(); err != nil {
+		return err
+	}
+	_, err := s.conn.Write(s.frame(message))
+	return err
+}
+
+// Close closes the connection to the syslog server.
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// String describes the sink in errors.
+func (s *SyslogSink) String() string {
+	if s.config.Network == "" {
+		return "syslog"
+	}
+	return fmt.Sprintf("syslog %s://%s", s.config.Network, s.config.Address)
+}
+
+// connect dials the syslog server and records the connection and its network; s.mu must be
+// held once the sink is shared.
+func (s *SyslogSink) connect() error {
+	conn, network, err := s.dial()
+	if err != nil {
+		return err
+	}
+	s.conn, s.network = conn, network
+	return nil
+}
+
+// dial connects to the configured server, or to the first local socket accepting the connection,
+// and returns the network it connected over.
+func (s *SyslogSink) dial() (net.Conn, string, error) {
+	if s.config.Network != "" {
+		conn, err := net.DialTimeout(s.config.Network, s.config.Address, 5*time.Second)
+		if err != nil {
+			return nil, "", fmt.Errorf("alailog: connect to syslog: %w", err)
+		}
+		return conn, s.config.Network, nil
+	}
+	for _, network := range []string{"unixgram", "unix"} {
+		for _, path := range syslogSockets {
+			if conn, err := net.Dial(network, path); err == nil {
+				return conn, network, nil
+			}
+		}
+	}
+	return nil, "", fmt.Errorf("alailog: connect to syslog: no local syslog socket")
+}
+
+// format renders the entry as a syslog message, without framing.
+func (s *SyslogSink) format(entry *Entry) string {
+	message := strings.TrimSuffix(entry.Message, "\n")
+	if len(entry.Fields) > 0 {
+		message += " " + entry.Fields.String()
+	}
+	if entry.Stack != "" {
+		message += "\n" + strings.TrimSuffix(entry.Stack, "\n")
+	}
+	priority := int(s.config.Facility)*8 + syslogSeverity(entry.Level)
+	if s.config.RFC3164 {
+		if s.config.Network == "" { // the local daemon adds the hostname
+			return fmt.Sprintf("<%d>%s %s[%d]: %s", priority, entry.Time.Format(time.Stamp), s.config.AppName, s.pid, message)
+		}
+		return fmt.Sprintf("<%d>%s %s %s[%d]: %s", priority, entry.Time.Format(time.Stamp), s.config.Hostname, s.config.AppName, s.pid, message)
+	}
+	return fmt.Sprintf("<%d>1 %s %s %s %d - - %s", priority, entry.Time.Format("2006-01-02T15:04:05.000000Z07:00"),
+		syslogHeader(s.config.Hostname), syslogHeader(s.config.AppName), s.pid, message)
+}
+
+// frame delimits the message on stream connections: RFC 5424 messages are prefixed with their
+// length (RFC 6587 octet counting) and RFC 3164 messages end with a newline. The framing follows
+// the network of the current connection, which for the local socket is only known once dialed.
+func (s *SyslogSink) frame(message string) []byte {
+	switch s.network {
+	case "tcp", "tcp4", "tcp6", "unix":
+		if s.config.RFC3164 {
+			return []byte(message + "\n")
+		}
+		return []byte(fmt.Sprintf("%d %s", len(message), message))
+	}
+	return []byte(message)
+}
+
+// syslogSeverity maps a level to its syslog severity.
+func syslogSeverity(level Level) int {
+	switch {
+	case level >= FatalLvl:
+		return 2 // critical
+	case level >= ErrorLvl:
+		return 3 // error
+	case level >= WarnLvl:
+		return 4 // warning
+	case level >= InfoLvl:
+		return 6 // informational
+	}
+	return 7 // debug
+}
+
+// syslogHeader returns a header field of an RFC 5424 message: printable ASCII without spaces,
+// or "-" when empty.
+func syslogHeader(value string) string {
+	value = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return -1
+		}
+		return r
+	}, value)
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,209 @@
+package alailog
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogSink_format(t *testing.T) {
+	entry := &Entry{
+		Time:    time.Date(2024, 3, 5, 14, 7, 9, 123456000, time.UTC),
+		Level:   WarnLvl,
+		Message: "disk almost full\n",
+		Fields:  Fields{"disk": "sda"},
+	}
+	tests := []struct {
+		name   string
+		config SyslogConfig
+		want   string
+	}{
+		{
+			name:   "RFC 5424",
+			config: SyslogConfig{Network: "udp", Facility: FacilityLocal0, Hostname: "web 1", AppName: "api"},
+			want:   "<132>1 2024-03-05T14:07:09.123456Z web1 api 42 - - disk almost full disk=sda",
+		},
+		{
+			name:   "RFC 3164",
+			config: SyslogConfig{Network: "udp", Facility: FacilityUser, Hostname: "web1", AppName: "api", RFC3164: true},
+			want:   "<12>Mar  5 14:07:09 web1 api[42]: disk almost full disk=sda",
+		},
+		{
+			name:   "RFC 3164 local",
+			config: SyslogConfig{Facility: FacilityDaemon, Hostname: "web1", AppName: "api", RFC3164: true},
+			want:   "<28>Mar  5 14:07:09 api[42]: disk almost full disk=sda",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SyslogSink{config: tt.config, pid: 42}
+			if got := s.format(entry); got != tt.want {
+				t.Errorf("format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyslogSink_frame(t *testing.T) {
+	tests := []struct {
+		network string
+		rfc3164 bool
+		want    string
+	}{
+		{"udp", false, "<14>msg"},
+		{"unixgram", true, "<14>msg"},
+		{"tcp", false, "7 <14>msg"},
+		{"tcp", true, "<14>msg\n"},
+		{"unix", false, "7 <14>msg"},
+		{"unix", true, "<14>msg\n"},
+	}
+	for _, tt := range tests {
+		s := &SyslogSink{config: SyslogConfig{RFC3164: tt.rfc3164}, network: tt.network}
+		if got := string(s.frame("<14>msg")); got != tt.want {
+			t.Errorf("frame() over %s (RFC 3164 %v) = %q, want %q", tt.network, tt.rfc3164, got, tt.want)
+		}
+	}
+}
+
+func TestSyslogSink_LocalStream(t *testing.T) {
+	dir, err := os.MkdirTemp("", "syslog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "log.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	defer listener.Close()
+	received := make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			received <- scanner.Text()
+		}
+	}()
+	defer func(sockets []string) { syslogSockets = sockets }(syslogSockets)
+	syslogSockets = []string{path}
+
+	sink, err := NewSyslogSink(SyslogConfig{Facility: FacilityUser, AppName: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	for _, message := range []string{"first", "second"} {
+		if err := sink.Write(&Entry{Time: time.Now(), Level: InfoLvl, Message: message + "\n"}); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	pattern := regexp.MustCompile(`^<14>\w{3} [ \d]\d \d\d:\d\d:\d\d test\[\d+\]: (first|second)$`)
+	for i := 0; i < 2; i++ {
+		select {
+		case got := <-received:
+			if !pattern.MatchString(got) {
+				t.Errorf("received %q, want it to match %s", got, pattern)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("messages not received as separate lines")
+		}
+	}
+}
+
+func TestSyslogSeverity(t *testing.T) {
+	want := map[Level]int{DebugLvl: 7, InfoLvl: 6, WarnLvl: 4, ErrorLvl: 3, FatalLvl: 2}
+	for level, severity := range want {
+		if got := syslogSeverity(level); got != severity {
+			t.Errorf("syslogSeverity(%s) = %d, want %d", level, got, severity)
+		}
+	}
+}
+
+func TestSyslogSink_Unixgram(t *testing.T) {
+	dir, err := os.MkdirTemp("", "syslog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "log.sock")
+	server, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram sockets unavailable: %v", err)
+	}
+	defer server.Close()
+
+	sink, err := NewSyslogSink(SyslogConfig{Network: "unixgram", Address: path, Facility: FacilityUser, AppName: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	l, _ := newTestLogger(t, InfoLvl)
+	l.AddSink(sink)
+	l.Error("failed\n")
+
+	buf := make([]byte, 1024)
+	server.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := server.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pattern := regexp.MustCompile(`^<11>1 \S+ \S+ test \d+ - - failed$`)
+	if got := string(buf[:n]); !pattern.MatchString(got) {
+		t.Errorf("received %q, want it to match %s", got, pattern)
+	}
+}
+
+func TestSyslogSink_TCPReconnect(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	received := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					received <- scanner.Text()
+				}
+			}()
+		}
+	}()
+
+	sink, err := NewSyslogSink(SyslogConfig{Network: "tcp", Address: listener.Addr().String(), Hostname: "web1", AppName: "test", RFC3164: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	for _, message := range []string{"first", "second"} {
+		if err := sink.Write(&Entry{Time: time.Now(), Level: InfoLvl, Message: message + "\n"}); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		select {
+		case got := <-received:
+			if !strings.Contains(got, " web1 test[") || !strings.HasSuffix(got, ": "+message) {
+				t.Errorf("received %q, want the %q message", got, message)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("message %q not received", message)
+		}
+		sink.conn.Close() // break the connection, forcing the sink to reconnect
+	}
+}
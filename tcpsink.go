@@ -0,0 +1,322 @@
+package alailog
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TCPSinkQueueSize is the number of entries a TCPSink holds in memory before new entries are dropped.
+const TCPSinkQueueSize = 1024
+
+// TCPSinkConfig configures a TCPSink.
+//
+//	Address: the host:port of the collector
+//	TLSConfig: the TLS configuration of the connection (nil connects without TLS)
+//	Formatter: renders each entry as one line (nil uses JSONFormatter)
+//	SpoolPath: the file keeping the entries written while disconnected ("" drops them)
+//	MaxSpoolBytes: the size of the spool file after which entries are dropped (0 uses 64 MiB)
+//	MinBackoff: the delay before the first reconnection attempt (0 uses 100 milliseconds)
+//	MaxBackoff: the longest delay between reconnection attempts (0 uses 30 seconds)
+type TCPSinkConfig struct {
+	Address       string
+	TLSConfig     *tls.Config
+	Formatter     Formatter
+	SpoolPath     string
+	MaxSpoolBytes int64
+	MinBackoff    time.Duration
+	MaxBackoff    time.Duration
+}
+
+// TCPSink sends entries to a collector over TCP or TLS, one line per entry. Entries are sent
+// from a background goroutine, so collector outages do not block logging: while disconnected,
+// the sink reconnects with exponential backoff and appends the entries to the spool file, which
+// is replayed in order once reconnected.
+type TCPSink struct {
+	config    TCPSinkConfig
+	queue     chan []byte
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	closed    atomic.Bool
+	dropped   atomic.Uint64
+	errs      asyncError
+
+	// owned by the goroutine sending the entries
+	conn        net.Conn
+	backoff     time.Duration
+	spool       *os.File
+	spoolSize   int64
+	spoolOffset int64
+}
+
+// NewTCPSink returns a sink sending entries to the collector at config.Address. The collector does
+// not need to be up: if the first connection fails, the sink keeps reconnecting in the background.
+// Entries spooled by a previous run are replayed first.
+//
+// Example usage:
+//
+//	sink, err := alailog.NewTCPSink(alailog.TCPSinkConfig{
+//	    Address:   "collector.example.com:6514",
+//	    TLSConfig: &tls.Config{},
+//	    SpoolPath: "/var/spool/api/logs.spool",
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	logger.AddSink(sink)
+//	defer logger.Close()
+func NewTCPSink(config TCPSinkConfig) (*TCPSink, error) {
+	if config.Address == "" {
+		return nil, errors.New("alailog: NewTCPSink: empty address")
+	}
+	if config.Formatter == nil {
+		config.Formatter = JSONFormatter{}
+	}
+	if config.MaxSpoolBytes <= 0 {
+		config.MaxSpoolBytes = 64 << 20
+	}
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = 100 * time.Millisecond
+	}
+	if config.MaxBackoff < config.MinBackoff {
+		config.MaxBackoff = max(30*time.Second, config.MinBackoff)
+	}
+	s := &TCPSink{
+		config:  config,
+		queue:   make(chan []byte, TCPSinkQueueSize),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+		backoff: config.MinBackoff,
+	}
+	if config.SpoolPath != "" {
+		spool, err := os.OpenFile(config.SpoolPath, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("alailog: open spool file: %w", err)
+		}
+		s.spool = spool
+		if err := s.repairSpool(); err != nil {
+			spool.Close()
+			return nil, fmt.Errorf("alailog: open spool file: %w", err)
+		}
+	}
+	s.connect()
+	go s.run()
+	return s, nil
+}
+
+// Write queues the entry for sending. It returns an error if the queue is full, or if sending
+// or spooling earlier entries failed since the last call.
+func (s *TCPSink) Write(entry *Entry) error {
+	if s.closed.Load() {
+		return errors.New("alailog: TCP sink closed")
+	}
+	line, err := s.config.Formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	if len(line) == 0 || line[len(line)-1] != '\n' {
+		line = append(line, '\n')
+	}
+	select {
+	case s.queue <- line:
+	default:
+		s.dropped.Add(1)
+		return errors.New("alailog: TCP sink queue full, entry dropped")
+	}
+	return s.errs.take()
+}
+
+// Dropped returns the number of entries the sink could neither send nor spool.
+func (s *TCPSink) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close sends or spools the queued entries, then closes the connection and the spool file.
+func (s *TCPSink) Close() error {
+	s.closeOnce.Do(func() {
+		s.closed.Store(true)
+		close(s.stop)
+	})
+	<-s.done
+	return s.errs.take()
+}
+
+// String describes the sink in errors.
+func (s *TCPSink) String() string {
+	return "tcp://" + s.config.Address
+}
+
+// run sends the queued entries and reconnects until the sink is closed.
+func (s *TCPSink) run() {
+	defer close(s.done)
+	reconnect := time.NewTimer(s.backoff)
+	defer reconnect.Stop()
+	reconnecting := true
+	if s.conn != nil {
+		stopTimer(reconnect)
+		reconnecting = false
+	}
+	for {
+		select {
+		case line := <-s.queue:
+			s.send(line)
+		case <-reconnect.C:
+			reconnecting = false
+			if s.conn == nil {
+				s.connect()
+			}
+		case <-s.stop:
+			for {
+				select {
+				case line := <-s.queue:
+					s.send(line)
+				default:
+					s.disconnect(nil)
+					if s.spool != nil {
+						s.errs.set(s.spool.Close())
+					}
+					return
+				}
+			}
+		}
+		if s.conn == nil && !reconnecting {
+			reconnect.Reset(s.backoff)
+			s.backoff = min(2*s.backoff, s.config.MaxBackoff)
+			reconnecting = true
+		}
+	}
+}
+
+// stopTimer stops the timer and drains a tick it already sent, so that a stale tick
+// cannot trigger a reconnection.
+func stopTimer(timer *time.Timer) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+}
+
+// connect connects to the collector and replays the spooled entries.
+func (s *TCPSink) connect() {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	var conn net.Conn
+	var err error
+	if s.config.TLSConfig != nil {
+		conn, err = tls.DialWithDialer(dialer, "tcp", s.config.Address, s.config.TLSConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", s.config.Address)
+	}
+	if err != nil {
+		s.errs.set(fmt.Errorf("alailog: connect to collector: %w", err))
+		return
+	}
+	s.conn = conn
+	s.backoff = s.config.MinBackoff
+	s.replay()
+}
+
+// send writes the line to the collector, or spools it while disconnected or replaying.
+func (s *TCPSink) send(line []byte) {
+	if s.conn != nil && s.spoolOffset == s.spoolSize {
+		if s.write(line) {
+			return
+		}
+	}
+	s.spoolLine(line)
+}
+
+// write writes the line to the connection, disconnecting when it fails.
+func (s *TCPSink) write(line []byte) bool {
+	s.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if _, err := s.conn.Write(line); err != nil {
+		s.disconnect(fmt.Errorf("alailog: send to collector: %w", err))
+		return false
+	}
+	return true
+}
+
+// disconnect closes the connection after a failure.
+func (s *TCPSink) disconnect(err error) {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+	s.errs.set(err)
+}
+
+// spoolLine appends the line to the spool file, or drops it when there is no room.
+func (s *TCPSink) spoolLine(line []byte) {
+	if s.spool == nil {
+		s.dropped.Add(1)
+		return
+	}
+	if s.spoolSize+int64(len(line)) > s.config.MaxSpoolBytes {
+		s.dropped.Add(1)
+		s.errs.set(errors.New("alailog: TCP sink spool full, entry dropped"))
+		return
+	}
+	n, err := s.spool.Write(line)
+	s.spoolSize += int64(n)
+	if err != nil {
+		s.dropped.Add(1)
+		s.errs.set(fmt.Errorf("alailog: write spool file: %w", err))
+	}
+}
+
+// repairSpool reads the size of the spool file, ending the last entry with a newline if a crash
+// cut it short, so that the entries appended next start on their own line.
+func (s *TCPSink) repairSpool() error {
+	info, err := s.spool.Stat()
+	if err != nil {
+		return err
+	}
+	s.spoolSize = info.Size()
+	if s.spoolSize == 0 {
+		return nil
+	}
+	last := make([]byte, 1)
+	if _, err := s.spool.ReadAt(last, s.spoolSize-1); err != nil {
+		return err
+	}
+	if last[0] != '\n' {
+		n, err := s.spool.Write([]byte{'\n'})
+		s.spoolSize += int64(n)
+		return err
+	}
+	return nil
+}
+
+// replay sends the spooled entries in order, and empties the spool file once they are all sent.
+func (s *TCPSink) replay() {
+	if s.spool == nil || s.spoolOffset == s.spoolSize {
+		return
+	}
+	reader := bufio.NewReader(io.NewSectionReader(s.spool, s.spoolOffset, s.spoolSize-s.spoolOffset))
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			if !s.write(line) {
+				return
+			}
+			s.spoolOffset += int64(len(line))
+		}
+		if err != nil {
+			break
+		}
+	}
+	if err := s.spool.Truncate(0); err != nil {
+		s.errs.set(fmt.Errorf("alailog: truncate spool file: %w", err))
+		return
+	}
+	s.spoolSize, s.spoolOffset = 0, 0
+}
@@ -0,0 +1,153 @@
+package alailog
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// collector accepts connections on a local TCP listener and passes on the lines it receives.
+type collector struct {
+	listener net.Listener
+	lines    chan string
+}
+
+func newCollector(t *testing.T, address string) *collector {
+	t.Helper()
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &collector{listener: listener, lines: make(chan string, 100)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					c.lines <- scanner.Text()
+				}
+			}()
+		}
+	}()
+	return c
+}
+
+// expect waits for the lines containing the messages, in order.
+func (c *collector) expect(t *testing.T, messages ...string) {
+	t.Helper()
+	for _, message := range messages {
+		select {
+		case line := <-c.lines:
+			if !strings.Contains(line, `"message":"`+message+`"`) {
+				t.Errorf("collector received %s, want the %q message", line, message)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("collector did not receive the %q message", message)
+		}
+	}
+}
+
+func TestTCPSink_Write(t *testing.T) {
+	c := newCollector(t, "127.0.0.1:0")
+	defer c.listener.Close()
+
+	sink, err := NewTCPSink(TCPSinkConfig{Address: c.listener.Addr().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, _ := newTestLogger(t, InfoLvl)
+	l.AddSink(sink)
+	l.Info("one\n")
+	l.Warn("two\n")
+	c.expect(t, "one", "two")
+	if err := l.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
+func TestStopTimer(t *testing.T) {
+	timer := time.NewTimer(time.Nanosecond)
+	time.Sleep(10 * time.Millisecond) // let the timer fire without receiving the tick
+	stopTimer(timer)
+	select {
+	case <-timer.C:
+		t.Error("stopTimer() left a stale tick")
+	default:
+	}
+
+	timer.Reset(time.Hour)
+	stopTimer(timer)
+	select {
+	case <-timer.C:
+		t.Error("stopTimer() let a stopped timer fire")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestTCPSink_Spool(t *testing.T) {
+	// reserve an address for the collector, which is down at first
+	c := newCollector(t, "127.0.0.1:0")
+	address := c.listener.Addr().String()
+	c.listener.Close()
+
+	spoolPath := filepath.Join(t.TempDir(), "logs.spool")
+	sink, err := NewTCPSink(TCPSinkConfig{
+		Address:    address,
+		SpoolPath:  spoolPath,
+		MinBackoff: 10 * time.Millisecond,
+		MaxBackoff: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	for _, message := range []string{"one", "two", "three"} {
+		sink.Write(&Entry{Time: time.Now(), Level: InfoLvl, Message: message})
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if b, _ := os.ReadFile(spoolPath); strings.Count(string(b), "\n") == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("entries not spooled while the collector is down")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	c = newCollector(t, address)
+	defer c.listener.Close()
+	c.expect(t, "one", "two", "three")
+	sink.Write(&Entry{Time: time.Now(), Level: InfoLvl, Message: "four"})
+	c.expect(t, "four")
+	if b, _ := os.ReadFile(spoolPath); len(b) != 0 {
+		t.Errorf("spool file holds %q after the replay, want it empty", b)
+	}
+}
+
+func TestTCPSink_ReplayPreviousRun(t *testing.T) {
+	c := newCollector(t, "127.0.0.1:0")
+	defer c.listener.Close()
+	spoolPath := filepath.Join(t.TempDir(), "logs.spool")
+	spooled := `{"message":"earlier"}` + "\n" + `{"message":"cut short"}`
+	if err := os.WriteFile(spoolPath, []byte(spooled), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sink, err := NewTCPSink(TCPSinkConfig{Address: c.listener.Addr().String(), SpoolPath: spoolPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	sink.Write(&Entry{Time: time.Now(), Level: InfoLvl, Message: "later"})
+	c.expect(t, "earlier", "cut short", "later")
+}
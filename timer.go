@@ -0,0 +1,249 @@
+package alailog
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Timer measures a single named operation. It is created by Logger.StartTimer
+// and records its duration in the logger's timer registry when stopped.
+type Timer struct {
+	name   string
+	start  time.Time
+	logger *Logger
+	once   sync.Once
+	took   time.Duration
+}
+
+// TimerStats summarizes the durations recorded for one timer name.
+type TimerStats struct {
+	Name  string
+	Count int64
+	Min   time.Duration
+	Max   time.Duration
+	Mean  time.Duration
+	P50   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+}
+
+// timerRegistry aggregates the durations recorded by stopped timers, per name.
+type timerRegistry struct {
+	mu    sync.Mutex
+	stats map[string]*timerAggregate
+}
+
+// timerAggregate holds the running statistics for one timer name.
+type timerAggregate struct {
+	count     int64
+	min       time.Duration
+	max       time.Duration
+	sum       time.Duration
+	histogram histogram
+}
+
+// StartTimer starts a named timer. Call Stop on the returned Timer to record
+// the elapsed time under that name.
+//
+// Example usage:
+//
+//	t := logger.StartTimer("db.query")
+//	rows, err := db.Query(q)
+//	t.Stop()
+func (l *Logger) StartTimer(name string) *Timer {
+	return &Timer{name: name, start: time.Now(), logger: l}
+}
+
+// Stop records the time elapsed since the timer was started and returns it.
+// Only the first call records a duration; later calls return the same value.
+func (t *Timer) Stop() time.Duration {
+	t.once.Do(func() {
+		t.took = t.logger.elapsedExecutionTime(t.start)
+		t.logger.RecordDuration(t.name, t.took)
+	})
+	return t.took
+}
+
+// Name returns the name the timer was started with.
+func (t *Timer) Name() string {
+	return t.name
+}
+
+// RecordDuration adds a duration measured elsewhere to the statistics of the named timer.
+func (l *Logger) RecordDuration(name string, d time.Duration) {
+	l.timers.mu.Lock()
+	defer l.timers.mu.Unlock()
+	if l.timers.stats == nil {
+		l.timers.stats = make(map[string]*timerAggregate)
+	}
+	agg, ok := l.timers.stats[name]
+	if !ok {
+		agg = &timerAggregate{min: d, max: d}
+		l.timers.stats[name] = agg
+	}
+	agg.count++
+	agg.sum += d
+	if d < agg.min {
+		agg.min = d
+	}
+	if d > agg.max {
+		agg.max = d
+	}
+	agg.histogram.add(float64(d))
+}
+
+// TimerStats returns the statistics recorded for the named timer and whether any were recorded.
+func (l *Logger) TimerStats(name string) (TimerStats, bool) {
+	l.timers.mu.Lock()
+	defer l.timers.mu.Unlock()
+	agg, ok := l.timers.stats[name]
+	if !ok {
+		return TimerStats{}, false
+	}
+	return agg.summary(name), true
+}
+
+// AllTimerStats returns the statistics of every recorded timer, sorted by name.
+func (l *Logger) AllTimerStats() []TimerStats {
+	l.timers.mu.Lock()
+	defer l.timers.mu.Unlock()
+	all := make([]TimerStats, 0, len(l.timers.stats))
+	for name, agg := range l.timers.stats {
+		all = append(all, agg.summary(name))
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
+
+// ResetTimers discards the statistics of every timer.
+func (l *Logger) ResetTimers() {
+	l.timers.mu.Lock()
+	defer l.timers.mu.Unlock()
+	l.timers.stats = nil
+}
+
+// LogTimerSummary logs a table with the statistics of every recorded timer at the Info level.
+func (l *Logger) LogTimerSummary() {
+	all := l.AllTimerStats()
+	if len(all) == 0 {
+		return
+	}
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIMER\tCOUNT\tMIN\tMAX\tMEAN\tP50\tP95\tP99")
+	for _, s := range all {
+		fmt.Fprintf(w, "%s\t%d\t%v\t%v\t%v\t%v\t%v\t%v\n", s.Name, s.Count, s.Min, s.Max, s.Mean, s.P50, s.P95, s.P99)
+	}
+	w.Flush()
+	l.Log(InfoLvl, "Timer summary:\n"+buf.String())
+}
+
+// LogTimerSummaryEvery logs the timer summary every interval until the returned function is called.
+func (l *Logger) LogTimerSummaryEvery(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				l.LogTimerSummary()
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+		})
+	}
+}
+
+// summary converts the running statistics into a TimerStats.
+func (a *timerAggregate) summary(name string) TimerStats {
+	return TimerStats{
+		Name:  name,
+		Count: a.count,
+		Min:   a.min,
+		Max:   a.max,
+		Mean:  a.sum / time.Duration(a.count),
+		P50:   time.Duration(a.histogram.quantile(0.50)),
+		P95:   time.Duration(a.histogram.quantile(0.95)),
+		P99:   time.Duration(a.histogram.quantile(0.99)),
+	}
+}
+
+// histogramBins is the maximum number of bins kept by a histogram.
+const histogramBins = 64
+
+// histogram is a streaming histogram (Ben-Haim & Tom-Tov) that keeps at most
+// histogramBins bins by merging the two closest bins whenever a value is added
+// past the limit. It estimates quantiles in constant memory.
+type histogram struct {
+	bins []histogramBin
+}
+
+// histogramBin is a centroid of the histogram with the number of values it stands for.
+type histogramBin struct {
+	value float64
+	count float64
+}
+
+// add inserts a value into the histogram.
+func (h *histogram) add(value float64) {
+	i := sort.Search(len(h.bins), func(i int) bool { return h.bins[i].value >= value })
+	if i < len(h.bins) && h.bins[i].value == value {
+		h.bins[i].count++
+		return
+	}
+	h.bins = append(h.bins, histogramBin{})
+	copy(h.bins[i+1:], h.bins[i:])
+	h.bins[i] = histogramBin{value: value, count: 1}
+	if len(h.bins) <= histogramBins {
+		return
+	}
+
+	closest := 0
+	for j := 1; j < len(h.bins)-1; j++ {
+		if h.bins[j+1].value-h.bins[j].value < h.bins[closest+1].value-h.bins[closest].value {
+			closest = j
+		}
+	}
+	a, b := h.bins[closest], h.bins[closest+1]
+	count := a.count + b.count
+	h.bins[closest] = histogramBin{value: (a.value*a.count + b.value*b.count) / count, count: count}
+	h.bins = append(h.bins[:closest+1], h.bins[closest+2:]...)
+}
+
+// quantile estimates the value below which the fraction q of the added values fall.
+func (h *histogram) quantile(q float64) float64 {
+	if len(h.bins) == 0 {
+		return 0
+	}
+	var total float64
+	for _, bin := range h.bins {
+		total += bin.count
+	}
+	target := q * total
+	var cumulative float64
+	for i, bin := range h.bins {
+		// each bin's values are spread evenly around its centroid
+		mid := cumulative + bin.count/2
+		if target <= mid {
+			if i == 0 {
+				return bin.value
+			}
+			prev := h.bins[i-1]
+			prevMid := cumulative - prev.count/2
+			return prev.value + (bin.value-prev.value)*(target-prevMid)/(mid-prevMid)
+		}
+		cumulative += bin.count
+	}
+	return h.bins[len(h.bins)-1].value
+}
@@ -0,0 +1,67 @@
+package alailog
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogger_TimerStats(t *testing.T) {
+	l, _ := newTestLogger(t, InfoLvl)
+	for i := 1; i <= 100; i++ {
+		l.RecordDuration("db.query", time.Duration(i)*time.Millisecond)
+	}
+
+	got, ok := l.TimerStats("db.query")
+	if !ok {
+		t.Fatal("TimerStats() found no stats for db.query")
+	}
+	if got.Count != 100 || got.Min != time.Millisecond || got.Max != 100*time.Millisecond {
+		t.Errorf("TimerStats() = %+v, want count 100, min 1ms, max 100ms", got)
+	}
+	if got.Mean != 50500*time.Microsecond {
+		t.Errorf("Mean = %v, want 50.5ms", got.Mean)
+	}
+	checks := []struct {
+		name string
+		got  time.Duration
+		want time.Duration
+	}{
+		{"P50", got.P50, 50 * time.Millisecond},
+		{"P95", got.P95, 95 * time.Millisecond},
+		{"P99", got.P99, 99 * time.Millisecond},
+	}
+	for _, c := range checks {
+		if diff := c.got - c.want; diff < -2*time.Millisecond || diff > 2*time.Millisecond {
+			t.Errorf("%s = %v, want about %v", c.name, c.got, c.want)
+		}
+	}
+
+	if _, ok := l.TimerStats("missing"); ok {
+		t.Error("TimerStats() found stats for a timer that never ran")
+	}
+}
+
+func TestTimer_Stop(t *testing.T) {
+	l, output := newTestLogger(t, InfoLvl)
+	timer := l.StartTimer("work")
+	first := timer.Stop()
+	if second := timer.Stop(); second != first {
+		t.Errorf("second Stop() = %v, want %v", second, first)
+	}
+	if s, _ := l.TimerStats("work"); s.Count != 1 {
+		t.Errorf("Count = %d, want 1", s.Count)
+	}
+
+	l.StartTimer("alpha").Stop()
+	l.LogTimerSummary()
+	got := output()
+	if !strings.Contains(got, "TIMER") || strings.Index(got, "alpha") > strings.Index(got, "work") {
+		t.Errorf("LogTimerSummary() = %q, want a table sorted by name", got)
+	}
+
+	l.ResetTimers()
+	if len(l.AllTimerStats()) != 0 {
+		t.Error("ResetTimers() kept timer stats")
+	}
+}
@@ -0,0 +1,163 @@
+package alailog
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tracer holds the state used by Trace: the function patterns that are
+// currently enabled and the nesting depth of every traced goroutine.
+type tracer struct {
+	mu       sync.Mutex
+	patterns []string
+	depth    map[uint64]int
+}
+
+// EnableTrace turns on Trace output for the functions matching any of the given patterns.
+// Patterns are matched against the full function name (e.g. "github.com/acme/app/db.(*Conn).Query")
+// and against the name without its import path (e.g. "db.(*Conn).Query"), using path.Match syntax.
+// Calling EnableTrace without patterns traces every function.
+//
+// Example usage:
+//
+//	logger.EnableTrace("db.*", "main.handle*")
+func (l *Logger) EnableTrace(patterns ...string) {
+	if len(patterns) == 0 {
+		patterns = []string{"*"}
+	}
+	l.tracer.mu.Lock()
+	defer l.tracer.mu.Unlock()
+	for _, pattern := range patterns {
+		if !containsString(l.tracer.patterns, pattern) {
+			l.tracer.patterns = append(l.tracer.patterns, pattern)
+		}
+	}
+}
+
+// DisableTrace removes the given patterns from the traced functions.
+// Calling DisableTrace without patterns turns tracing off entirely.
+func (l *Logger) DisableTrace(patterns ...string) {
+	l.tracer.mu.Lock()
+	defer l.tracer.mu.Unlock()
+	if len(patterns) == 0 {
+		l.tracer.patterns = nil
+		return
+	}
+	kept := l.tracer.patterns[:0]
+	for _, pattern := range l.tracer.patterns {
+		if !containsString(patterns, pattern) {
+			kept = append(kept, pattern)
+		}
+	}
+	l.tracer.patterns = kept
+}
+
+// Trace logs the entry into the calling function and returns a function that logs its exit
+// together with the elapsed time. The calling function name is detected automatically.
+// Optional args are logged with the entry line. Nested traced calls on the same goroutine
+// are indented by their depth. Trace output is written at the Debug level, and only for
+// functions enabled with EnableTrace.
+//
+// Example usage:
+//
+//	func (s *Store) Get(key string) string {
+//	    defer logger.Trace(key)()
+//	    ...
+//	}
+func (l *Logger) Trace(args ...interface{}) func() {
+	if !l.tracing() {
+		return func() {}
+	}
+	name := l.GetFunctionName(2) // 2 steps up the call stack to get the function that calls Trace
+	if !l.traced(name) {
+		return func() {}
+	}
+
+	id := goroutineID()
+	depth := l.enterTrace(id)
+	indent := strings.Repeat("  ", depth)
+	start := time.Now()
+	l.Log(DebugLvl, fmt.Sprintf("%sEnter %s(%s)\n", indent, name, formatTraceArgs(args)))
+
+	return func() {
+		elapsed := l.elapsedExecutionTime(start)
+		l.exitTrace(id)
+		l.Log(DebugLvl, fmt.Sprintf("%sExit %s took %v\n", indent, name, elapsed))
+	}
+}
+
+// tracing reports whether any trace pattern is enabled.
+func (l *Logger) tracing() bool {
+	l.tracer.mu.Lock()
+	defer l.tracer.mu.Unlock()
+	return len(l.tracer.patterns) > 0
+}
+
+// traced reports whether the named function matches an enabled trace pattern.
+func (l *Logger) traced(name string) bool {
+	l.tracer.mu.Lock()
+	defer l.tracer.mu.Unlock()
+	for _, pattern := range l.tracer.patterns {
+		if matchFunctionName(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// enterTrace increments the nesting depth of the goroutine and returns the depth before the call.
+func (l *Logger) enterTrace(id uint64) int {
+	l.tracer.mu.Lock()
+	defer l.tracer.mu.Unlock()
+	if l.tracer.depth == nil {
+		l.tracer.depth = make(map[uint64]int)
+	}
+	depth := l.tracer.depth[id]
+	l.tracer.depth[id] = depth + 1
+	return depth
+}
+
+// exitTrace decrements the nesting depth of the goroutine, forgetting it once it reaches zero.
+func (l *Logger) exitTrace(id uint64) {
+	l.tracer.mu.Lock()
+	defer l.tracer.mu.Unlock()
+	if l.tracer.depth[id] <= 1 {
+		delete(l.tracer.depth, id)
+		return
+	}
+	l.tracer.depth[id]--
+}
+
+// matchFunctionName matches a trace pattern against a function name, with and without its import path.
+func matchFunctionName(pattern, name string) bool {
+	if pattern == "*" {
+		return true
+	}
+	if ok, _ := path.Match(pattern, name); ok {
+		return true
+	}
+	ok, _ := path.Match(pattern, name[strings.LastIndex(name, "/")+1:])
+	return ok
+}
+
+// formatTraceArgs joins the traced arguments with commas.
+func formatTraceArgs(args []interface{}) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = fmt.Sprintf("%v", arg)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// containsString reports whether s is present in list.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package alailog
+
+import (
+	"strings"
+	"testing"
+)
+
+func traceOuter(l *Logger) {
+	defer l.Trace("a", 1)()
+	traceInner(l)
+}
+
+func traceInner(l *Logger) {
+	defer l.Trace()()
+}
+
+func TestLogger_Trace(t *testing.T) {
+	l, output := newTestLogger(t, DebugLvl)
+	l.EnableTrace()
+	traceOuter(l)
+
+	lines := strings.Split(strings.TrimSuffix(output(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Trace() wrote %d lines, want 4:\n%s", len(lines), output())
+	}
+	wants := []string{
+		"Enter github.com/josephalai/alailog.traceOuter(a, 1)",
+		"  Enter github.com/josephalai/alailog.traceInner()",
+		"  Exit github.com/josephalai/alailog.traceInner took ",
+		"Exit github.com/josephalai/alailog.traceOuter took ",
+	}
+	for i, want := range wants {
+		if !strings.HasPrefix(lines[i], want) {
+			t.Errorf("line %d = %q, want prefix %q", i, lines[i], want)
+		}
+	}
+}
+
+func TestLogger_TracePatterns(t *testing.T) {
+	tests := []struct {
+		name      string
+		enable    []string
+		disable   []string
+		wantOuter bool
+		wantInner bool
+	}{
+		{name: "disabled", wantOuter: false, wantInner: false},
+		{name: "all", enable: []string{}, wantOuter: true, wantInner: true},
+		{name: "short name", enable: []string{"alailog.traceInner"}, wantOuter: false, wantInner: true},
+		{name: "full name", enable: []string{"github.com/josephalai/alailog.traceO*"}, wantOuter: true, wantInner: false},
+		{name: "removed", enable: []string{"*.traceOuter", "*.traceInner"}, disable: []string{"*.traceInner"}, wantOuter: true, wantInner: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, output := newTestLogger(t, DebugLvl)
+			if tt.enable != nil {
+				l.EnableTrace(tt.enable...)
+			}
+			if tt.disable != nil {
+				l.DisableTrace(tt.disable...)
+			}
+			traceOuter(l)
+			got := output()
+			if strings.Contains(got, "traceOuter") != tt.wantOuter {
+				t.Errorf("traceOuter traced = %v, want %v:\n%s", !tt.wantOuter, tt.wantOuter, got)
+			}
+			if strings.Contains(got, "traceInner") != tt.wantInner {
+				t.Errorf("traceInner traced = %v, want %v:\n%s", !tt.wantInner, tt.wantInner, got)
+			}
+		})
+	}
+}
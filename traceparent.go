@@ -0,0 +1,166 @@
+package alailog
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// W3C Trace Context header names.
+const (
+	TraceparentHeader = "traceparent"
+	TracestateHeader  = "tracestate"
+)
+
+// ErrInvalidTraceparent is returned by ParseTraceparent for malformed headers.
+var ErrInvalidTraceparent = errors.New("alailog: invalid traceparent")
+
+// TraceContext holds the W3C Trace Context of a request.
+//
+//	TraceID: the 32 hex digit ID of the whole trace
+//	SpanID: the 16 hex digit ID of the current span
+//	ParentID: the 16 hex digit ID of the caller's span, empty for a root span
+//	Flags: the trace flags; bit 0 is the sampled flag
+//	TraceState: the vendor-specific tracestate header, passed along unchanged
+type TraceContext struct {
+	TraceID    string
+	SpanID     string
+	ParentID   string
+	Flags      byte
+	TraceState string
+}
+
+func init() {
+	RegisterContextExtractor(traceContextFields)
+}
+
+// ParseTraceparent parses a traceparent header ("00-<trace-id>-<parent-id>-<flags>")
+// and a tracestate header into a TraceContext. The parent ID of the header becomes
+// the SpanID of the returned context.
+func ParseTraceparent(traceparent, tracestate string) (TraceContext, error) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 {
+		return TraceContext{}, ErrInvalidTraceparent
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if !isHex(version, 2) || version == "ff" || (version == "00" && len(parts) != 4) {
+		return TraceContext{}, ErrInvalidTraceparent
+	}
+	if !isHex(traceID, 32) || traceID == strings.Repeat("0", 32) {
+		return TraceContext{}, ErrInvalidTraceparent
+	}
+	if !isHex(spanID, 16) || spanID == strings.Repeat("0", 16) {
+		return TraceContext{}, ErrInvalidTraceparent
+	}
+	if !isHex(flags, 2) {
+		return TraceContext{}, ErrInvalidTraceparent
+	}
+	flagByte, _ := hex.DecodeString(flags)
+	return TraceContext{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		Flags:      flagByte[0],
+		TraceState: strings.TrimSpace(tracestate),
+	}, nil
+}
+
+// NewTraceContext starts a new sampled trace with random trace and span IDs.
+func NewTraceContext() TraceContext {
+	return TraceContext{TraceID: randomHex(16), SpanID: randomHex(8), Flags: 1}
+}
+
+// Child returns the context of a new span of the same trace, whose parent is tc.
+func (tc TraceContext) Child() TraceContext {
+	return TraceContext{
+		TraceID:    tc.TraceID,
+		SpanID:     randomHex(8),
+		ParentID:   tc.SpanID,
+		Flags:      tc.Flags,
+		TraceState: tc.TraceState,
+	}
+}
+
+// Sampled reports whether the sampled flag is set.
+func (tc TraceContext) Sampled() bool {
+	return tc.Flags&1 == 1
+}
+
+// Traceparent formats the context as a version 00 traceparent header.
+func (tc TraceContext) Traceparent() string {
+	return fmt.Sprintf("00-%s-%s-%02x", tc.TraceID, tc.SpanID, tc.Flags)
+}
+
+// Inject sets the traceparent and tracestate headers of an outgoing request.
+func (tc TraceContext) Inject(header http.Header) {
+	header.Set(TraceparentHeader, tc.Traceparent())
+	if tc.TraceState != "" {
+		header.Set(TracestateHeader, tc.TraceState)
+	}
+}
+
+// ContextWithTraceContext returns a copy of ctx carrying the trace context.
+// Entries logged with the returned context get trace_id and span_id fields.
+func ContextWithTraceContext(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, traceContextKey, tc)
+}
+
+// TraceContextFromContext returns the trace context carried by ctx.
+func TraceContextFromContext(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(traceContextKey).(TraceContext)
+	return tc, ok
+}
+
+// TraceMiddleware returns an http.Handler that extracts the W3C Trace Context of each
+// request, or starts a new trace when the request has none or an invalid one, and starts
+// a span for the request. The request context carries the trace context and the logger,
+// so handlers can log correlated entries with:
+//
+//	alailog.InfoCtx(r.Context(), "handled")
+//
+// The traceparent of the request's span is also set on the response.
+func (l *Logger) TraceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tc, err := ParseTraceparent(r.Header.Get(TraceparentHeader), r.Header.Get(TracestateHeader))
+		if err != nil {
+			tc = NewTraceContext()
+		} else {
+			tc = tc.Child()
+		}
+		tc.Inject(w.Header())
+		ctx := ContextWithTraceContext(WithContext(r.Context(), l), tc)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// traceContextFields is the extractor adding the trace and span IDs of the context's trace context.
+func traceContextFields(ctx context.Context) Fields {
+	tc, ok := TraceContextFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return Fields{TraceIDField: tc.TraceID, SpanIDField: tc.SpanID}
+}
+
+// isHex reports whether s is made of n lowercase hex digits.
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// randomHex returns n random bytes encoded as hex.
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,94 @@
+package alailog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		name        string
+		traceparent string
+		want        TraceContext
+		wantErr     bool
+	}{
+		{
+			name:        "valid",
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			want:        TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", Flags: 1, TraceState: "congo=t61rcWkgMzE"},
+		},
+		{
+			name:        "future version with extra fields",
+			traceparent: "cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-extra",
+			want:        TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", TraceState: "congo=t61rcWkgMzE"},
+		},
+		{name: "empty", traceparent: "", wantErr: true},
+		{name: "uppercase", traceparent: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", wantErr: true},
+		{name: "zero trace id", traceparent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01", wantErr: true},
+		{name: "zero span id", traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", wantErr: true},
+		{name: "version ff", traceparent: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantErr: true},
+		{name: "version 00 extra fields", traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-x", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTraceparent(tt.traceparent, " congo=t61rcWkgMzE ")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTraceparent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseTraceparent() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTraceContext_Traceparent(t *testing.T) {
+	tc := NewTraceContext()
+	parsed, err := ParseTraceparent(tc.Traceparent(), "")
+	if err != nil || parsed != tc {
+		t.Errorf("ParseTraceparent(%q) = %+v, %v, want %+v", tc.Traceparent(), parsed, err, tc)
+	}
+	child := tc.Child()
+	if child.TraceID != tc.TraceID || child.ParentID != tc.SpanID || child.SpanID == tc.SpanID {
+		t.Errorf("Child() = %+v, want a new span of trace %s", child, tc.TraceID)
+	}
+}
+
+func TestLogger_TraceMiddleware(t *testing.T) {
+	l, output := newTestLogger(t, InfoLvl)
+	var got TraceContext
+	handler := l.TraceMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = TraceContextFromContext(r.Context())
+		InfoCtx(r.Context(), "handled\n")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || got.ParentID != "00f067aa0ba902b7" {
+		t.Errorf("trace context = %+v, want a child of the incoming span", got)
+	}
+	if rec.Header().Get(TraceparentHeader) != got.Traceparent() {
+		t.Errorf("response traceparent = %q, want %q", rec.Header().Get(TraceparentHeader), got.Traceparent())
+	}
+	want := "handled span_id=" + got.SpanID + " trace_id=" + got.TraceID + "\n"
+	if out := output(); out != want {
+		t.Errorf("handler logged %q, want %q", out, want)
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if got.TraceID == "" || got.ParentID != "" || !strings.Contains(output(), got.TraceID) {
+		t.Errorf("trace context = %+v, want a new root trace", got)
+	}
+}
+
+func TestTraceContextFields(t *testing.T) {
+	if fields := traceContextFields(context.Background()); fields != nil {
+		t.Errorf("traceContextFields() = %v, want nil", fields)
+	}
+}